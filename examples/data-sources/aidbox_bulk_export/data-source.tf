@@ -0,0 +1,17 @@
+# Snapshot the clinical data of a cohort changed since the start of the year.
+data "aidbox_bulk_export" "cohort" {
+  level    = "group"
+  group_id = "compliance-cohort"
+  types    = ["Patient", "Encounter", "Observation"]
+  since    = "2024-01-01T00:00:00Z"
+
+  output_dir = "${path.module}/snapshots"
+
+  timeouts {
+    read = "2h"
+  }
+}
+
+output "exported_files" {
+  value = [for f in data.aidbox_bulk_export.cohort.files : f.path]
+}
@@ -0,0 +1,8 @@
+data "aidbox_db_index_suggestions" "observation_date" {
+  resource_type = "Observation"
+  search_param  = "date"
+}
+
+output "suggested_indexes" {
+  value = data.aidbox_db_index_suggestions.observation_date.suggestions[*].sql
+}
@@ -0,0 +1,10 @@
+# Run the ADT mapping on a sample message on every plan, before it is applied.
+data "aidbox_hl7v2_parse" "adt_sample" {
+  message      = file("${path.module}/samples/adt_a01.hl7")
+  strict       = true
+  mapping_body = aidbox_mapping.adt.body
+}
+
+output "adt_sample_bundle" {
+  value = jsondecode(data.aidbox_hl7v2_parse.adt_sample.result)
+}
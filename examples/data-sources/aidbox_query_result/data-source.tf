@@ -0,0 +1,10 @@
+data "aidbox_query_result" "born_1980" {
+  name = aidbox_query.patients_by_birth_year.id
+  params = {
+    year = "1980"
+  }
+}
+
+output "patient_ids" {
+  value = data.aidbox_query_result.born_1980.rows[*].id
+}
@@ -0,0 +1,10 @@
+data "aidbox_value_set_expansion" "urgent_triage" {
+  url = aidbox_value_set.urgent_triage.url
+
+  lifecycle {
+    postcondition {
+      condition     = self.total == 2
+      error_message = "The urgent triage value set must contain exactly two codes."
+    }
+  }
+}
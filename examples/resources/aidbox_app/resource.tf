@@ -0,0 +1,30 @@
+variable "reports_app_secret" {
+  type      = string
+  sensitive = true
+}
+
+resource "aidbox_app" "reports" {
+  id = "myorg.reports"
+
+  endpoint = {
+    url    = "http://reports:8080"
+    secret = var.reports_app_secret
+  }
+
+  operations = {
+    daily-report = {
+      method = "GET"
+      path   = ["Patient", ":id", "$daily-report"]
+    }
+    rebuild-report = {
+      method = "POST"
+      path   = ["Patient", ":id", "$rebuild-report"]
+    }
+  }
+
+  subscriptions = {
+    Patient = {
+      handler = "patient-changed"
+    }
+  }
+}
@@ -0,0 +1,25 @@
+# The default provider is configured with the Multibox server.
+resource "aidbox_box" "tenant_a" {
+  id           = "tenant-a"
+  description  = "Tenant A"
+  fhir_version = "4.0.1"
+}
+
+# A provider alias manages the resources inside the box.
+provider "aidbox" {
+  alias         = "tenant_a"
+  instance_url  = aidbox_box.tenant_a.url
+  client_id     = aidbox_box.tenant_a.admin_client_id
+  client_secret = aidbox_box.tenant_a.admin_client_secret
+}
+
+resource "aidbox_search_parameter" "tenant_a_mrn" {
+  provider = aidbox.tenant_a
+
+  id         = "patient-mrn"
+  url        = "https://tenant-a.example.org/fhir/SearchParameter/patient-mrn"
+  name       = "mrn"
+  type       = "token"
+  base       = ["Patient"]
+  expression = "Patient.identifier.where(system='urn:tenant-a:mrn')"
+}
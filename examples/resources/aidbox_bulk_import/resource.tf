@@ -0,0 +1,33 @@
+# Import a synthetic dataset the instance downloads itself.
+resource "aidbox_bulk_import" "synthea" {
+  inputs = [
+    {
+      resource_type = "Patient"
+      url           = "https://storage.example.org/synthea/Patient.ndjson.gz"
+    },
+    {
+      resource_type = "Encounter"
+      url           = "https://storage.example.org/synthea/Encounter.ndjson.gz"
+    },
+  ]
+
+  timeouts {
+    create = "2h"
+  }
+}
+
+# Upload local fixtures; editing a file loads it again.
+resource "aidbox_bulk_import" "fixtures" {
+  inputs = [
+    { file = "${path.module}/fixtures/practitioners.ndjson" },
+    { file = "${path.module}/fixtures/organizations.ndjson.gz" },
+  ]
+
+  triggers = {
+    environment = "staging"
+  }
+}
+
+output "patients_loaded" {
+  value = aidbox_bulk_import.synthea.resource_counts["Patient"]
+}
@@ -0,0 +1,21 @@
+resource "aidbox_code_system" "triage" {
+  id     = "triage-level"
+  url    = "http://example.org/fhir/CodeSystem/triage-level"
+  name   = "TriageLevel"
+  status = "active"
+
+  concepts = [
+    { code = "1", display = "Resuscitation" },
+    { code = "2", display = "Emergent" },
+    { code = "3", display = "Urgent" },
+  ]
+}
+
+# Large code systems are loaded from a CSV file with the columns code,
+# display and definition, and uploaded in batches of chunk_size concepts.
+resource "aidbox_code_system" "local_labs" {
+  id           = "local-labs"
+  url          = "http://example.org/fhir/CodeSystem/local-labs"
+  concepts_csv = "${path.module}/local-labs.csv"
+  chunk_size   = 500
+}
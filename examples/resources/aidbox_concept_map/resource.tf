@@ -0,0 +1,25 @@
+resource "aidbox_concept_map" "triage_to_acuity" {
+  id  = "triage-to-acuity"
+  url = "http://example.org/fhir/ConceptMap/triage-to-acuity"
+
+  groups = [
+    {
+      source = aidbox_code_system.triage.url
+      target = "http://terminology.hl7.org/CodeSystem/v3-ActPriority"
+      elements = [
+        {
+          code    = "1"
+          targets = [{ code = "EM", equivalence = "wider" }]
+        },
+      ]
+    },
+  ]
+}
+
+# Mappings can also be loaded from a CSV file with the columns
+# source_system, source_code, target_system, target_code and equivalence.
+resource "aidbox_concept_map" "local_labs_to_loinc" {
+  id           = "local-labs-to-loinc"
+  url          = "http://example.org/fhir/ConceptMap/local-labs-to-loinc"
+  mappings_csv = "${path.module}/local-labs-to-loinc.csv"
+}
@@ -0,0 +1,12 @@
+resource "aidbox_db_index" "patient_family" {
+  name       = "patient_name_family"
+  table      = "patient"
+  expression = "(resource#>>'{name,0,family}')"
+}
+
+resource "aidbox_db_index" "observation_resource" {
+  name       = "observation_resource_gin"
+  table      = "observation"
+  method     = "gin"
+  expression = "resource jsonb_path_ops"
+}
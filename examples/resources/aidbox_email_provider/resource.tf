@@ -0,0 +1,22 @@
+# Send notifications through an SMTP relay.
+resource "aidbox_email_provider" "default" {
+  from = "no-reply@example.org"
+
+  smtp = {
+    host     = "smtp.example.org"
+    port     = 587
+    tls      = true
+    username = "aidbox"
+    password = var.smtp_password
+  }
+}
+
+# An additional SendGrid provider.
+resource "aidbox_email_provider" "sendgrid" {
+  name = "sendgrid"
+  from = "no-reply@example.org"
+
+  sendgrid = {
+    api_key = var.sendgrid_api_key
+  }
+}
@@ -0,0 +1,17 @@
+# Install an implementation guide from the package registry.
+resource "aidbox_fhir_package" "us_core" {
+  name    = "hl7.fhir.us.core"
+  version = "6.1.0"
+
+  timeouts {
+    create = "45m"
+    update = "45m"
+  }
+}
+
+# Upload and install a local package archive.
+resource "aidbox_fhir_package" "internal_ig" {
+  name        = "example.fhir.internal"
+  version     = "1.2.0"
+  source_file = "${path.module}/packages/example.fhir.internal-1.2.0.tgz"
+}
@@ -0,0 +1,5 @@
+resource "aidbox_hl7v2_config" "adt" {
+  id         = "adt"
+  is_strict  = true
+  mapping_id = aidbox_mapping.adt.id
+}
@@ -0,0 +1,25 @@
+variable "pr_number" {
+  type = number
+}
+
+resource "aidbox_license" "preview" {
+  name = "pr-${var.pr_number}"
+  type = "development"
+}
+
+# One ephemeral environment per pull request.
+resource "aidbox_hosted_instance" "preview" {
+  license_id = aidbox_license.preview.id
+  name       = "pr-${var.pr_number}"
+}
+
+provider "aidbox" {
+  alias         = "preview"
+  instance_url  = aidbox_hosted_instance.preview.url
+  client_id     = aidbox_hosted_instance.preview.admin_client_id
+  client_secret = aidbox_hosted_instance.preview.admin_client_secret
+}
+
+output "preview_url" {
+  value = aidbox_hosted_instance.preview.url
+}
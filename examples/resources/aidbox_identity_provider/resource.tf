@@ -0,0 +1,27 @@
+variable "keycloak_client_secret" {
+  type      = string
+  sensitive = true
+}
+
+resource "aidbox_identity_provider" "keycloak" {
+  id                 = "keycloak"
+  title              = "Hospital SSO"
+  authorize_endpoint = "https://sso.example.org/realms/hospital/protocol/openid-connect/auth"
+  token_endpoint     = "https://sso.example.org/realms/hospital/protocol/openid-connect/token"
+  userinfo_endpoint  = "https://sso.example.org/realms/hospital/protocol/openid-connect/userinfo"
+  userinfo_source    = "userinfo-endpoint"
+  scopes             = ["openid", "profile", "email"]
+
+  client = {
+    id           = "aidbox"
+    secret       = var.keycloak_client_secret
+    redirect_uri = "https://aidbox.example.org/auth/callback/keycloak"
+  }
+
+  userinfo_mapping = {
+    "userName"        = "preferred_username"
+    "email"           = "email"
+    "name.givenName"  = "given_name"
+    "name.familyName" = "family_name"
+  }
+}
@@ -0,0 +1,31 @@
+# Purge expired sessions every night.
+resource "aidbox_job" "purge_sessions" {
+  id  = "purge-sessions"
+  at  = "02:30"
+  sql = "DELETE FROM session WHERE (resource->>'exp')::bigint < extract(epoch FROM now())"
+}
+
+# Ask a backend app to sync orders every 15 minutes.
+resource "aidbox_job" "sync_orders" {
+  id    = "sync-orders"
+  every = "15m"
+
+  http = {
+    url = "https://orders.example.org/aidbox/sync"
+    headers = {
+      Authorization = "Bearer ${var.orders_token}"
+    }
+  }
+}
+
+variable "orders_token" {
+  type      = string
+  sensitive = true
+}
+
+check "jobs_healthy" {
+  assert {
+    condition     = aidbox_job.purge_sessions.last_error == null
+    error_message = "The last run of purge-sessions failed: ${coalesce(aidbox_job.purge_sessions.last_error, "")}"
+  }
+}
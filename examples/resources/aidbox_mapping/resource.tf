@@ -0,0 +1,27 @@
+# Map the patient of ADT messages to a FHIR Patient.
+resource "aidbox_mapping" "adt" {
+  id = "adt-to-fhir"
+  body = jsonencode({
+    resourceType = "Bundle"
+    type         = "transaction"
+    entry = [{
+      request = {
+        method = "PUT"
+        url    = "$ \"/Patient/\" + parsed.patient_group.patient.identifier.0.value"
+      }
+      resource = {
+        resourceType = "Patient"
+        name = [{
+          family = "$ parsed.patient_group.patient.name.0.family.surname"
+          given  = ["$ parsed.patient_group.patient.name.0.given"]
+        }]
+      }
+    }]
+  })
+}
+
+# Templates can also be kept as YAML files.
+resource "aidbox_mapping" "orders" {
+  id   = "orm-to-fhir"
+  body = jsonencode(yamldecode(file("${path.module}/mappings/orm.yaml")))
+}
@@ -0,0 +1,10 @@
+resource "aidbox_notification_template" "reset_password" {
+  id      = "reset-password"
+  subject = "Reset your password, {{user.name.givenName}}"
+  template = <<-EOT
+    <p>Hello {{user.name.givenName}},</p>
+    {{#if link}}
+    <p><a href="{{link}}">Reset your password</a></p>
+    {{/if}}
+  EOT
+}
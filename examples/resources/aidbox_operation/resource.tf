@@ -0,0 +1,6 @@
+resource "aidbox_operation" "patient_summary" {
+  id     = "patient-summary"
+  method = "GET"
+  path   = ["Patient", ":id", "$summary"]
+  app_id = aidbox_app.reports.id
+}
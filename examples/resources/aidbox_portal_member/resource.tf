@@ -0,0 +1,14 @@
+locals {
+  platform_team = {
+    "alice@example.org" = "admin"
+    "bob@example.org"   = "member"
+  }
+}
+
+resource "aidbox_portal_member" "platform" {
+  for_each = local.platform_team
+
+  project_id = aidbox_portal_project.platform.id
+  email      = each.key
+  role       = each.value
+}
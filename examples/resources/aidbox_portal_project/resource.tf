@@ -0,0 +1,4 @@
+resource "aidbox_portal_project" "platform" {
+  name        = "Platform team"
+  description = "Licenses of the shared Aidbox environments"
+}
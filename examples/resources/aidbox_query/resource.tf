@@ -0,0 +1,16 @@
+resource "aidbox_query" "patients_by_birth_year" {
+  id          = "patients-by-birth-year"
+  query       = "SELECT id, resource#>>'{birthDate}' AS birth_date FROM patient WHERE resource#>>'{birthDate}' LIKE {{params.year}} || '%' LIMIT {{params.limit}}"
+  count_query = "SELECT count(*) FROM patient WHERE resource#>>'{birthDate}' LIKE {{params.year}} || '%'"
+
+  params = {
+    year = {
+      type     = "string"
+      required = true
+    }
+    limit = {
+      type    = "integer"
+      default = "100"
+    }
+  }
+}
@@ -0,0 +1,21 @@
+# FHIR SearchParameter on an extension field
+resource "aidbox_search_parameter" "birth_place" {
+  id          = "patient-birth-place"
+  url         = "https://example.org/fhir/SearchParameter/patient-birth-place"
+  name        = "birth-place"
+  type        = "string"
+  base        = ["Patient"]
+  expression  = "Patient.extension.where(url='http://hl7.org/fhir/StructureDefinition/patient-birthPlace').value.address.city"
+  description = "Search patients by city of birth"
+  reindex     = true
+}
+
+# Aidbox-native SearchParameter
+resource "aidbox_search_parameter" "mrn" {
+  id     = "Patient.mrn"
+  format = "aidbox"
+  name   = "mrn"
+  type   = "token"
+  base   = ["Patient"]
+  paths  = ["identifier.0.value"]
+}
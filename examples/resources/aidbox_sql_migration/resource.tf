@@ -0,0 +1,18 @@
+resource "aidbox_sql_migration" "active_patients_view" {
+  id   = "0001_active_patients_view"
+  up   = <<-SQL
+    CREATE OR REPLACE VIEW active_patients AS
+      SELECT id, resource->'name' AS name
+      FROM patient
+      WHERE resource->>'active' = 'true'
+  SQL
+  down = "DROP VIEW IF EXISTS active_patients"
+}
+
+resource "aidbox_sql_migration" "active_patients_count" {
+  id   = "0002_active_patients_count"
+  up   = "CREATE OR REPLACE FUNCTION active_patients_count() RETURNS bigint AS 'SELECT count(*) FROM active_patients' LANGUAGE sql"
+  down = "DROP FUNCTION IF EXISTS active_patients_count()"
+
+  depends_on = [aidbox_sql_migration.active_patients_view]
+}
@@ -0,0 +1,24 @@
+resource "aidbox_structure_definition" "us_patient" {
+  id = "us-patient"
+  content = jsonencode({
+    url            = "http://example.org/StructureDefinition/us-patient"
+    name           = "USPatient"
+    status         = "active"
+    kind           = "resource"
+    abstract       = false
+    type           = "Patient"
+    baseDefinition = "http://hl7.org/fhir/StructureDefinition/Patient"
+    derivation     = "constraint"
+    differential = {
+      element = [
+        { id = "Patient.birthDate", path = "Patient.birthDate", min = 1 },
+      ]
+    }
+  })
+}
+
+resource "aidbox_structure_definition" "lab_observation" {
+  id      = "lab-observation"
+  format  = "fhir_schema"
+  content = file("${path.module}/schemas/lab-observation.json")
+}
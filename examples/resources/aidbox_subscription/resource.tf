@@ -0,0 +1,13 @@
+resource "aidbox_subscription" "female_patients" {
+  id       = "female-patients"
+  endpoint = "https://events.example.com/aidbox"
+
+  triggers = {
+    Patient = {
+      events = ["create", "update"]
+      filter = jsonencode({ match = { gender = "female" } })
+    }
+  }
+
+  payload_content = "full-resource"
+}
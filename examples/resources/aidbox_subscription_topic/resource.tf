@@ -0,0 +1,12 @@
+resource "aidbox_subscription_topic" "patients" {
+  id  = "patient-changes"
+  url = "http://example.org/SubscriptionTopic/patient-changes"
+
+  triggers = [
+    {
+      resource_type          = "Patient"
+      supported_interactions = ["create", "update"]
+      fhir_path_criteria     = "active = true"
+    },
+  ]
+}
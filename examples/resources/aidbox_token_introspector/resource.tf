@@ -0,0 +1,21 @@
+resource "aidbox_token_introspector" "okta" {
+  id       = "okta"
+  type     = "jwt"
+  issuer   = "https://example.okta.com/oauth2/default"
+  jwks_uri = "https://example.okta.com/oauth2/default/v1/keys"
+}
+
+variable "introspection_authorization" {
+  type      = string
+  sensitive = true
+}
+
+resource "aidbox_token_introspector" "legacy_gateway" {
+  id   = "legacy-gateway"
+  type = "opaque"
+
+  introspection_endpoint = {
+    url           = "https://gateway.example.org/oauth2/introspect"
+    authorization = var.introspection_authorization
+  }
+}
@@ -0,0 +1,28 @@
+variable "webhook_token" {
+  type      = string
+  sensitive = true
+}
+
+resource "aidbox_topic_destination" "patients_kafka" {
+  id    = "patient-changes-kafka"
+  topic = aidbox_subscription_topic.patients.url
+
+  kafka = {
+    kafka_topic       = "aidbox-patients"
+    bootstrap_servers = "kafka-1:9092,kafka-2:9092"
+  }
+}
+
+resource "aidbox_topic_destination" "patients_webhook" {
+  id    = "patient-changes-webhook"
+  topic = aidbox_subscription_topic.patients.url
+
+  webhook = {
+    endpoint              = "https://events.example.com/aidbox"
+    timeout               = 5000
+    max_messages_in_batch = 20
+    headers = {
+      Authorization = "Bearer ${var.webhook_token}"
+    }
+  }
+}
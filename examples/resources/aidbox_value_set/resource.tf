@@ -0,0 +1,14 @@
+resource "aidbox_value_set" "urgent_triage" {
+  id  = "urgent-triage"
+  url = "http://example.org/fhir/ValueSet/urgent-triage"
+
+  include = [
+    {
+      system = aidbox_code_system.triage.url
+      concepts = [
+        { code = "1" },
+        { code = "2" },
+      ]
+    },
+  ]
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// App is an Aidbox App, which routes operations and subscriptions of the box
+// to an external backend.
+type App struct {
+	ResourceType  string                     `json:"resourceType"`
+	ID            string                     `json:"id,omitempty"`
+	APIVersion    int64                      `json:"apiVersion"`
+	Type          string                     `json:"type"`
+	Endpoint      AppEndpoint                `json:"endpoint"`
+	Operations    map[string]AppOperation    `json:"operations,omitempty"`
+	Subscriptions map[string]AppSubscription `json:"subscriptions,omitempty"`
+}
+
+// AppEndpoint is the backend requests are forwarded to.
+type AppEndpoint struct {
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// AppOperation is a route served by an App.
+type AppOperation struct {
+	Method string        `json:"method"`
+	Path   []PathSegment `json:"path"`
+	Action string        `json:"action,omitempty"`
+}
+
+// AppSubscription forwards the changes of a resource type to a handler of an
+// App.
+type AppSubscription struct {
+	Handler string `json:"handler"`
+}
+
+// Operation is a standalone Aidbox Operation. The first element of Request
+// is the lower-case HTTP method, the rest is the route.
+type Operation struct {
+	ResourceType string        `json:"resourceType"`
+	ID           string        `json:"id,omitempty"`
+	Request      []PathSegment `json:"request"`
+	Action       string        `json:"action,omitempty"`
+	App          *Reference    `json:"app,omitempty"`
+}
+
+// PathSegment is a segment of an operation route: either a literal or, when
+// Name is set, a named parameter encoded as {"name": "..."}.
+type PathSegment struct {
+	Literal string
+	Name    string
+}
+
+func (s PathSegment) MarshalJSON() ([]byte, error) {
+	if s.Name != "" {
+		return json.Marshal(map[string]string{"name": s.Name})
+	}
+	return json.Marshal(s.Literal)
+}
+
+func (s *PathSegment) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.Literal); err == nil {
+		return nil
+	}
+
+	var param struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &param); err != nil || param.Name == "" {
+		return fmt.Errorf("invalid path segment %s", data)
+	}
+	s.Name = param.Name
+	return nil
+}
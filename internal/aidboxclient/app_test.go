@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPathSegmentJSON(t *testing.T) {
+	path := []PathSegment{{Literal: "Patient"}, {Name: "id"}, {Literal: "$report"}}
+
+	data, err := json.Marshal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `["Patient",{"name":"id"},"$report"]` {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var decoded []PathSegment
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, path) {
+		t.Errorf("expected %v, got %v", path, decoded)
+	}
+
+	if err := json.Unmarshal([]byte(`[{"id": "x"}]`), &decoded); err == nil {
+		t.Error("expected an error for a parameter without a name")
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/http"
+)
+
+// AsyncStatus is the state of an asynchronous request, as reported by its
+// status URL.
+type AsyncStatus struct {
+	// Done is false while the status URL answers 202 Accepted.
+	Done bool
+	// Progress is the X-Progress header of an in-progress request.
+	Progress string
+	// Body is the result of a completed request.
+	Body []byte
+}
+
+// asyncHeader asks the server to process a request asynchronously.
+func asyncHeader(contentType string) http.Header {
+	header := http.Header{}
+	header.Set("Prefer", "respond-async")
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return header
+}
+
+// asyncStatusURL returns the status URL of a request started with
+// asyncHeader, or "" when the server completed it synchronously.
+func asyncStatusURL(resp *instanceResponse) string {
+	if resp.StatusCode != http.StatusAccepted {
+		return ""
+	}
+	return resp.Header.Get("Content-Location")
+}
+
+// GetAsyncStatus checks the status URL of an asynchronous request. Failed
+// requests are returned as *APIError.
+func (c *AidboxHTTPClient) GetAsyncStatus(ctx context.Context, statusURL string) (AsyncStatus, error) {
+	resp, err := c.doInstance(ctx, "GET", statusURL, "", nil)
+	if err != nil {
+		return AsyncStatus{}, err
+	}
+
+	if resp.StatusCode == http.StatusAccepted {
+		return AsyncStatus{Progress: resp.Header.Get("X-Progress")}, nil
+	}
+	return AsyncStatus{Done: true, Body: resp.Body}, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestInstallFHIRPackageAsync(t *testing.T) {
+	polls := 0
+	var client *AidboxHTTPClient
+	client = newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fhir/$fhir-package-install":
+			if r.Header.Get("Prefer") != "respond-async" {
+				t.Errorf("expected Prefer: respond-async, got %q", r.Header.Get("Prefer"))
+			}
+			// Status URLs are absolute.
+			w.Header().Set("Content-Location", client.InstanceURL+"/async/1")
+			w.WriteHeader(http.StatusAccepted)
+		case "/async/1":
+			polls++
+			if polls < 2 {
+				w.Header().Set("X-Progress", "loading resources")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_, _ = w.Write([]byte(`{"resourceType": "OperationOutcome"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+	statusURL, err := client.InstallFHIRPackage(ctx, "hl7.fhir.us.core@6.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statusURL != client.InstanceURL+"/async/1" {
+		t.Fatalf("unexpected status URL %q", statusURL)
+	}
+
+	status, err := client.GetAsyncStatus(ctx, statusURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Done || status.Progress != "loading resources" {
+		t.Errorf("expected an in-progress status, got %+v", status)
+	}
+
+	status, err = client.GetAsyncStatus(ctx, statusURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Done {
+		t.Errorf("expected a completed status, got %+v", status)
+	}
+}
+
+func TestGetAsyncStatusForeignHost(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/async/1" || r.URL.RawQuery != "_format=json" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"resourceType": "OperationOutcome"}`))
+	})
+
+	// Behind a proxy, Aidbox reports its internal address.
+	status, err := client.GetAsyncStatus(context.Background(), "http://localhost:8080/async/1?_format=json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Done {
+		t.Errorf("expected a completed status, got %+v", status)
+	}
+}
+
+func TestInstancePath(t *testing.T) {
+	client := NewClient("", "", nil)
+	client.InstanceURL = "https://example.org/aidbox"
+
+	testCases := map[string]string{
+		"/fhir/Patient/pt-1":                          "/fhir/Patient/pt-1",
+		"https://example.org/aidbox/async/1":          "/async/1",
+		"http://localhost:8080/async/1":               "/async/1",
+		"http://localhost:8080/aidbox/async/1?x=y":    "/async/1?x=y",
+		"https://other.example.org/$export-status/42": "/$export-status/42",
+	}
+	for p, want := range testCases {
+		if got := client.instancePath(p); got != want {
+			t.Errorf("%s: expected %q, got %q", p, want, got)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+// IdentityProvider is an external OpenID Connect or OAuth 2.0 provider users
+// can log in with.
+type IdentityProvider struct {
+	ResourceType      string                 `json:"resourceType"`
+	ID                string                 `json:"id,omitempty"`
+	Type              string                 `json:"type"`
+	Title             string                 `json:"title,omitempty"`
+	Active            bool                   `json:"active"`
+	AuthorizeEndpoint string                 `json:"authorize_endpoint"`
+	TokenEndpoint     string                 `json:"token_endpoint"`
+	UserinfoEndpoint  string                 `json:"userinfo_endpoint,omitempty"`
+	UserinfoSource    string                 `json:"userinfo-source,omitempty"`
+	Scopes            []string               `json:"scopes,omitempty"`
+	Client            IdentityProviderClient `json:"client"`
+	ToScim            map[string]interface{} `json:"toScim,omitempty"`
+}
+
+// IdentityProviderClient is the client Aidbox is registered as at the
+// identity provider.
+type IdentityProviderClient struct {
+	ID          string `json:"id"`
+	Secret      string `json:"secret,omitempty"`
+	RedirectURI string `json:"redirect_uri,omitempty"`
+}
+
+// TokenIntrospector accepts access tokens issued by an external
+// authorization server.
+type TokenIntrospector struct {
+	ResourceType          string                      `json:"resourceType"`
+	ID                    string                      `json:"id,omitempty"`
+	Type                  string                      `json:"type"`
+	JWT                   *TokenIntrospectorJWT       `json:"jwt,omitempty"`
+	JWKSURI               string                      `json:"jwks_uri,omitempty"`
+	IntrospectionEndpoint *TokenIntrospectionEndpoint `json:"introspection_endpoint,omitempty"`
+}
+
+// TokenIntrospectorJWT validates JWTs of an issuer.
+type TokenIntrospectorJWT struct {
+	Issuer string `json:"iss"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// TokenIntrospectionEndpoint is an RFC 7662 introspection endpoint for
+// opaque tokens.
+type TokenIntrospectionEndpoint struct {
+	URL           string `json:"url"`
+	Authorization string `json:"authorization,omitempty"`
+}
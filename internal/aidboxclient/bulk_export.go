@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Levels of a bulk export.
+const (
+	BulkExportSystem  = "system"
+	BulkExportPatient = "patient"
+	BulkExportGroup   = "group"
+)
+
+// BulkExportRequest describes the data of a FHIR bulk $export.
+type BulkExportRequest struct {
+	// Level is BulkExportSystem, BulkExportPatient or BulkExportGroup.
+	Level string
+	// GroupID is the Group whose members are exported at group level.
+	GroupID string
+	// Types limits the export to these resource types (_type).
+	Types []string
+	// Since limits the export to resources changed after this FHIR instant
+	// (_since).
+	Since string
+}
+
+// BulkExportManifest is the result of a completed $export.
+type BulkExportManifest struct {
+	TransactionTime     string           `json:"transactionTime"`
+	Request             string           `json:"request"`
+	RequiresAccessToken bool             `json:"requiresAccessToken"`
+	Output              []BulkExportFile `json:"output"`
+	Error               []BulkExportFile `json:"error"`
+}
+
+// BulkExportFile is an NDJSON file of a manifest. Error files contain
+// OperationOutcome resources.
+type BulkExportFile struct {
+	Type  string `json:"type"`
+	URL   string `json:"url"`
+	Count *int64 `json:"count,omitempty"`
+}
+
+// bulkExportPath returns the path of the $export operation of a request.
+func bulkExportPath(req BulkExportRequest) (string, error) {
+	var path string
+	switch req.Level {
+	case BulkExportSystem, "":
+		path = "/fhir/$export"
+	case BulkExportPatient:
+		path = "/fhir/Patient/$export"
+	case BulkExportGroup:
+		if req.GroupID == "" {
+			return "", errors.New("a group export requires a group ID")
+		}
+		path = "/fhir/Group/" + url.PathEscape(req.GroupID) + "/$export"
+	default:
+		return "", fmt.Errorf("unknown export level %q", req.Level)
+	}
+
+	query := url.Values{}
+	if len(req.Types) > 0 {
+		query.Set("_type", strings.Join(req.Types, ","))
+	}
+	if req.Since != "" {
+		query.Set("_since", req.Since)
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return path, nil
+}
+
+// StartBulkExport kicks off a $export and returns its status URL.
+func (c *AidboxHTTPClient) StartBulkExport(ctx context.Context, req BulkExportRequest) (string, error) {
+	path, err := bulkExportPath(req)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.doInstanceWithHeader(ctx, "GET", path, asyncHeader(""), nil)
+	if err != nil {
+		return "", err
+	}
+	statusURL := asyncStatusURL(resp)
+	if statusURL == "" {
+		return "", fmt.Errorf("the server did not start an asynchronous export (status %d)", resp.StatusCode)
+	}
+	return statusURL, nil
+}
+
+// ParseBulkExportManifest parses the body of a completed $export.
+func ParseBulkExportManifest(body []byte) (BulkExportManifest, error) {
+	var manifest BulkExportManifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return BulkExportManifest{}, fmt.Errorf("failed to parse export manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// DownloadExportFile streams a file of a manifest into w. When the manifest
+// requires an access token, its files are served by the instance: they are
+// requested on the configured instance URL, as the manifest may name the
+// internal address of the instance, and with the instance credentials.
+// Other URLs, e.g. signed storage URLs, are requested as they are and without
+// credentials.
+func (c *AidboxHTTPClient) DownloadExportFile(ctx context.Context, fileURL string, requiresAccessToken bool, w io.Writer) error {
+	endpoint := fileURL
+	if requiresAccessToken {
+		if c.InstanceURL == "" {
+			return ErrInstanceNotConfigured
+		}
+		endpoint = c.instanceEndpoint(fileURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/fhir+ndjson")
+	if requiresAccessToken {
+		req.SetBasicAuth(c.ClientID, c.ClientSecret)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return newNetworkError(req.URL.Scheme+"://"+req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return &APIError{StatusCode: resp.StatusCode, Status: resp.Status, Body: Redact(string(body))}
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", fileURL, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBulkExportPath(t *testing.T) {
+	testCases := map[string]struct {
+		req  BulkExportRequest
+		want string
+	}{
+		"system":  {BulkExportRequest{}, "/fhir/$export"},
+		"patient": {BulkExportRequest{Level: BulkExportPatient, Types: []string{"Patient", "Observation"}}, "/fhir/Patient/$export?_type=Patient%2CObservation"},
+		"group":   {BulkExportRequest{Level: BulkExportGroup, GroupID: "cohort", Since: "2024-01-01T00:00:00Z"}, "/fhir/Group/cohort/$export?_since=2024-01-01T00%3A00%3A00Z"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := bulkExportPath(tc.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+
+	if _, err := bulkExportPath(BulkExportRequest{Level: BulkExportGroup}); err == nil {
+		t.Error("expected an error for a group export without a group ID")
+	}
+}
+
+func TestDownloadExportFile(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			t.Error("expected the instance credentials")
+		}
+		if r.URL.Path != "/export/Patient.ndjson" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"resourceType": "Patient", "id": "pt-1"}` + "\n"))
+	})
+
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected no credentials to be sent to external storage")
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(storage.Close)
+
+	// Files that require the access token are served by the instance, also
+	// when the manifest names its internal address.
+	for _, fileURL := range []string{
+		client.InstanceURL + "/export/Patient.ndjson",
+		"http://localhost:8080/export/Patient.ndjson",
+	} {
+		var out bytes.Buffer
+		if err := client.DownloadExportFile(context.Background(), fileURL, true, &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != `{"resourceType": "Patient", "id": "pt-1"}`+"\n" {
+			t.Errorf("unexpected content %q", out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	err := client.DownloadExportFile(context.Background(), storage.URL+"/Patient.ndjson", false, &out)
+	if apiErr, ok := err.(*APIError); !ok || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("expected a 403 APIError, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// maxNDJSONLine is the largest resource ImportNDJSON accepts.
+const maxNDJSONLine = 64 << 20
+
+// BulkImportInput is an NDJSON file of one resource type, downloaded by
+// the instance from URL.
+type BulkImportInput struct {
+	ResourceType string `json:"resourceType"`
+	URL          string `json:"url"`
+}
+
+// BulkImportResult summarizes an import.
+type BulkImportResult struct {
+	// Counts is the number of resources loaded per resource type.
+	Counts map[string]int64
+	// Errors are the failures per resource type, sorted by resource type.
+	Errors []BulkImportError
+}
+
+// BulkImportError reports the resources of a type that failed to load.
+type BulkImportError struct {
+	ResourceType string
+	Count        int64
+	// Message describes the first failure.
+	Message string
+}
+
+func (r *BulkImportResult) loaded(resourceType string, count int64) {
+	if r.Counts == nil {
+		r.Counts = map[string]int64{}
+	}
+	r.Counts[resourceType] += count
+}
+
+func (r *BulkImportResult) failed(resourceType string, count int64, message string) {
+	for i := range r.Errors {
+		if r.Errors[i].ResourceType == resourceType {
+			r.Errors[i].Count += count
+			return
+		}
+	}
+	r.Errors = append(r.Errors, BulkImportError{ResourceType: resourceType, Count: count, Message: message})
+	sort.Slice(r.Errors, func(i, j int) bool { return r.Errors[i].ResourceType < r.Errors[j].ResourceType })
+}
+
+// Total returns the number of resources loaded.
+func (r BulkImportResult) Total() int64 {
+	var total int64
+	for _, count := range r.Counts {
+		total += count
+	}
+	return total
+}
+
+// Merge adds the counts and errors of other to r.
+func (r *BulkImportResult) Merge(other BulkImportResult) {
+	for resourceType, count := range other.Counts {
+		r.loaded(resourceType, count)
+	}
+	for _, e := range other.Errors {
+		r.failed(e.ResourceType, e.Count, e.Message)
+	}
+}
+
+// bulkImportStatus is the result of a completed $import, in the format of
+// a FHIR bulk data manifest.
+type bulkImportStatus struct {
+	Output []struct {
+		Type  string `json:"type"`
+		Count int64  `json:"count"`
+	} `json:"output"`
+	Error []struct {
+		Type    string `json:"type"`
+		Count   int64  `json:"count"`
+		URL     string `json:"url"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// ParseBulkImportResult parses the body of a completed $import.
+func ParseBulkImportResult(body []byte) (BulkImportResult, error) {
+	var status bulkImportStatus
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, &status); err != nil {
+			return BulkImportResult{}, fmt.Errorf("failed to parse import result: %w", err)
+		}
+	}
+
+	var result BulkImportResult
+	for _, o := range status.Output {
+		result.loaded(o.Type, o.Count)
+	}
+	for _, e := range status.Error {
+		message := e.Message
+		if message == "" && e.URL != "" {
+			message = "see " + e.URL
+		}
+		result.failed(e.Type, e.Count, message)
+	}
+	return result, nil
+}
+
+// StartBulkImport starts a $import of NDJSON files the instance downloads
+// itself. contentEncoding is "gzip" for compressed files or "" for plain
+// ones. It returns the status URL of the import, or "" and the result when
+// the import completed synchronously.
+func (c *AidboxHTTPClient) StartBulkImport(ctx context.Context, id, contentEncoding string, inputs []BulkImportInput) (string, BulkImportResult, error) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":              id,
+		"inputFormat":     "application/fhir+ndjson",
+		"contentEncoding": contentEncoding,
+		"mode":            "bulk",
+		"inputs":          inputs,
+	})
+	if err != nil {
+		return "", BulkImportResult{}, fmt.Errorf("failed to create JSON request body: %w", err)
+	}
+
+	resp, err := c.doInstanceWithHeader(ctx, "POST", "/fhir/$import", asyncHeader("application/json"), bytes.NewReader(payload))
+	if err != nil {
+		return "", BulkImportResult{}, err
+	}
+	if statusURL := asyncStatusURL(resp); statusURL != "" {
+		return statusURL, BulkImportResult{}, nil
+	}
+
+	result, err := ParseBulkImportResult(resp.Body)
+	return "", result, err
+}
+
+// LoadNDJSON loads an NDJSON file the instance downloads from sourceURL
+// with $load, which is faster than $import but skips validation. When
+// resourceType is set, the lines of the file may omit resourceType. $load
+// responds with the number of resources loaded per resource type.
+func (c *AidboxHTTPClient) LoadNDJSON(ctx context.Context, sourceURL, resourceType string) (BulkImportResult, error) {
+	path := "/$load"
+	if resourceType != "" {
+		path = "/" + url.PathEscape(resourceType) + "/$load"
+	}
+
+	var counts map[string]int64
+	if err := c.instanceJSON(ctx, "POST", path, map[string]string{"source": sourceURL}, &counts); err != nil {
+		return BulkImportResult{}, err
+	}
+
+	var result BulkImportResult
+	for t, count := range counts {
+		result.loaded(t, count)
+	}
+	return result, nil
+}
+
+// ImportNDJSON uploads NDJSON resources, optionally gzip-compressed, with
+// batch requests of at most chunkSize resources. Resources with an ID are
+// created or replaced, others are created. Entries the instance rejects
+// are reported in the result rather than as an error.
+func (c *AidboxHTTPClient) ImportNDJSON(ctx context.Context, content io.Reader, chunkSize int) (BulkImportResult, error) {
+	if chunkSize <= 0 {
+		chunkSize = 500
+	}
+
+	reader := bufio.NewReader(content)
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return BulkImportResult{}, fmt.Errorf("failed to decompress NDJSON: %w", err)
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+
+	var result BulkImportResult
+	var entries []bundleEntry
+	var types []string
+	flush := func() error {
+		if len(entries) == 0 {
+			return nil
+		}
+		statuses, err := c.batchStatuses(ctx, entries)
+		if err != nil {
+			return err
+		}
+		for i, status := range statuses {
+			if strings.HasPrefix(status, "2") {
+				result.loaded(types[i], 1)
+			} else {
+				result.failed(types[i], 1, fmt.Sprintf("%s %s: %s", entries[i].Request.Method, entries[i].Request.URL, status))
+			}
+		}
+		entries, types = entries[:0], types[:0]
+		return nil
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var header struct {
+			ResourceType string `json:"resourceType"`
+			ID           string `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil || header.ResourceType == "" {
+			return result, fmt.Errorf("line %d is not a FHIR resource", line)
+		}
+
+		request := bundleRequest{Method: "POST", URL: "/" + url.PathEscape(header.ResourceType)}
+		if header.ID != "" {
+			request = bundleRequest{Method: "PUT", URL: request.URL + "/" + url.PathEscape(header.ID)}
+		}
+		entries = append(entries, bundleEntry{Resource: json.RawMessage(append([]byte(nil), raw...)), Request: request})
+		types = append(types, header.ResourceType)
+
+		if len(entries) == chunkSize {
+			if err := flush(); err != nil {
+				return result, fmt.Errorf("failed to upload resources up to line %d: %w", line, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("failed to read NDJSON after line %d: %w", line, err)
+	}
+	if err := flush(); err != nil {
+		return result, fmt.Errorf("failed to upload resources up to line %d: %w", line, err)
+	}
+	return result, nil
+}
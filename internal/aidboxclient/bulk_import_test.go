@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImportNDJSON(t *testing.T) {
+	var sizes []int
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var bundle struct {
+			Entry []bundleEntry `json:"entry"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&bundle); err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(bundle.Entry))
+
+		entries := make([]string, 0, len(bundle.Entry))
+		for _, e := range bundle.Entry {
+			status := "201 Created"
+			if strings.HasSuffix(e.Request.URL, "/bad") {
+				status = "422 Unprocessable Entity"
+			}
+			entries = append(entries, fmt.Sprintf(`{"response": {"status": %q}}`, status))
+		}
+		_, _ = fmt.Fprintf(w, `{"resourceType": "Bundle", "entry": [%s]}`, strings.Join(entries, ","))
+	})
+
+	var content bytes.Buffer
+	gz := gzip.NewWriter(&content)
+	_, _ = gz.Write([]byte(`{"resourceType": "Patient", "id": "pt-1"}
+{"resourceType": "Patient"}
+
+{"resourceType": "Observation", "id": "bad"}
+`))
+	_ = gz.Close()
+
+	result, err := client.ImportNDJSON(context.Background(), &content, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sizes) != "[2 1]" {
+		t.Errorf("unexpected batch sizes %v", sizes)
+	}
+	if fmt.Sprint(result.Counts) != "map[Patient:2]" {
+		t.Errorf("unexpected counts %v", result.Counts)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].ResourceType != "Observation" || result.Errors[0].Message != "PUT /Observation/bad: 422 Unprocessable Entity" {
+		t.Errorf("unexpected errors %+v", result.Errors)
+	}
+
+	_, err = client.ImportNDJSON(context.Background(), strings.NewReader("{\"resourceType\": \"Patient\"}\nnot json\n"), 2)
+	if err == nil || err.Error() != "line 2 is not a FHIR resource" {
+		t.Errorf("expected an invalid line error, got %v", err)
+	}
+}
+
+func TestParseBulkImportResult(t *testing.T) {
+	result, err := ParseBulkImportResult([]byte(`{
+		"output": [{"type": "Patient", "count": 10}, {"type": "Encounter", "count": 4}, {"type": "Patient", "count": 5}],
+		"error": [{"type": "Encounter", "count": 2, "url": "https://example.org/errors.ndjson"}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(result.Counts) != "map[Encounter:4 Patient:15]" {
+		t.Errorf("unexpected counts %v", result.Counts)
+	}
+	if fmt.Sprintf("%+v", result.Errors) != "[{ResourceType:Encounter Count:2 Message:see https://example.org/errors.ndjson}]" {
+		t.Errorf("unexpected errors %+v", result.Errors)
+	}
+}
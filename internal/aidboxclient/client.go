@@ -11,9 +11,15 @@ import (
 )
 
 type AidboxHTTPClient struct {
+	// Endpoint and Token are used for the portal RPC API.
 	Endpoint string
 	Token    string
-	Client   *http.Client
+	// InstanceURL, ClientID and ClientSecret are used for the API of an
+	// Aidbox instance.
+	InstanceURL  string
+	ClientID     string
+	ClientSecret string
+	Client       *http.Client
 }
 
 type Creator struct {
@@ -73,11 +79,17 @@ type APIResponse struct {
 	}
 }
 
-func NewClient(endpoint, token string) *AidboxHTTPClient {
+// NewClient returns a client for the given endpoint. When httpClient is nil,
+// a client using http.DefaultTransport with redacted logging is used.
+func NewClient(endpoint, token string, httpClient *http.Client) *AidboxHTTPClient {
+	if httpClient == nil {
+		httpClient = &http.Client{Transport: NewLoggingTransport(http.DefaultTransport)}
+	}
+
 	return &AidboxHTTPClient{
 		Endpoint: endpoint,
 		Token:    token,
-		Client:   http.DefaultClient,
+		Client:   httpClient,
 	}
 }
 
@@ -97,7 +109,7 @@ func (c *AidboxHTTPClient) CreateLicense(ctx context.Context, name, product, lic
 	apiResp, parseErr := parseYAMLResponse(bodyBytes)
 	if parseErr != nil {
 		// Log and handle any parsing errors
-		tflog.Error(ctx, "Failed to parse YAML response", map[string]interface{}{"error": parseErr, "body": string(bodyBytes)})
+		tflog.Error(ctx, "Failed to parse YAML response", map[string]interface{}{"error": parseErr.Error(), "body": Redact(string(bodyBytes))})
 		return LicenseResponse{}, parseErr
 	}
 
@@ -123,7 +135,7 @@ func (c *AidboxHTTPClient) GetLicense(ctx context.Context, licenseID string) (Li
 	apiResp, parseErr := parseYAMLResponse(bodyBytes)
 	if parseErr != nil {
 		// Log and handle any parsing errors
-		tflog.Error(ctx, "Failed to parse YAML response", map[string]interface{}{"error": parseErr, "body": string(bodyBytes)})
+		tflog.Error(ctx, "Failed to parse YAML response", map[string]interface{}{"error": parseErr.Error(), "body": Redact(string(bodyBytes))})
 		return LicenseResponse{}, parseErr
 	}
 
@@ -150,7 +162,7 @@ func (c *AidboxHTTPClient) makeAPICall(ctx context.Context, method string, param
 		return nil, 0, fmt.Errorf("failed to create YAML request body: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", c.Endpoint, strings.NewReader(string(yamlData)))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.Endpoint, strings.NewReader(string(yamlData)))
 	if err != nil {
 		tflog.Error(ctx, "Failed to create HTTP request", map[string]interface{}{"error": err})
 		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
@@ -160,8 +172,10 @@ func (c *AidboxHTTPClient) makeAPICall(ctx context.Context, method string, param
 
 	resp, err := c.Client.Do(req)
 	if err != nil {
-		tflog.Error(ctx, "API call failed", map[string]interface{}{"error": err})
-		return nil, resp.StatusCode, fmt.Errorf("API call failed: %w", err)
+		// resp is always nil when Do returns an error.
+		netErr := newNetworkError(c.Endpoint, err)
+		tflog.Error(ctx, "API call failed", map[string]interface{}{"error": netErr.Error(), "kind": string(netErr.Kind)})
+		return nil, 0, netErr
 	}
 	defer resp.Body.Close()
 
@@ -172,11 +186,12 @@ func (c *AidboxHTTPClient) makeAPICall(ctx context.Context, method string, param
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		redactedBody := Redact(string(bodyBytes))
 		tflog.Error(ctx, "API response error", map[string]interface{}{
 			"status": resp.Status,
-			"body":   string(bodyBytes),
+			"body":   redactedBody,
 		})
-		return nil, resp.StatusCode, fmt.Errorf("API response error: %s; Body: %s", resp.Status, string(bodyBytes))
+		return nil, resp.StatusCode, &APIError{StatusCode: resp.StatusCode, Status: resp.Status, Body: redactedBody}
 	}
 
 	return bodyBytes, resp.StatusCode, nil
@@ -184,11 +199,19 @@ func (c *AidboxHTTPClient) makeAPICall(ctx context.Context, method string, param
 
 func parseYAMLResponse(bodyBytes []byte) (LicenseResponse, error) {
 	var apiResp APIResponse
-	if err := yaml.Unmarshal(bodyBytes, &apiResp); err != nil {
-		return LicenseResponse{}, fmt.Errorf("failed to parse YAML response: %w", err)
+	if err := unmarshalYAML(bodyBytes, &apiResp); err != nil {
+		return LicenseResponse{}, err
 	}
 	return LicenseResponse{
 		License: apiResp.Result.License,
 		JWT:     apiResp.Result.JWT,
 	}, nil
 }
+
+func unmarshalYAML(bodyBytes []byte, out interface{}) error {
+	if err := yaml.Unmarshal(bodyBytes, out); err != nil {
+		// YAML errors may quote the offending value, which can be a secret.
+		return fmt.Errorf("failed to parse YAML response: %s", Redact(err.Error()))
+	}
+	return nil
+}
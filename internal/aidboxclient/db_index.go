@@ -0,0 +1,224 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DBIndex is a Postgres index on a resource table.
+type DBIndex struct {
+	Schema       string
+	Name         string
+	Table        string
+	Method       string
+	Expression   string
+	Unique       bool
+	Where        string
+	Concurrently bool
+}
+
+// DBIndexState is an index as reported by pg_indexes.
+type DBIndexState struct {
+	Table      string
+	Definition string
+	Method     string
+	Unique     bool
+	// Expression and Where are normalized by Postgres and may differ
+	// textually from the statement the index was created with.
+	Expression string
+	Where      string
+}
+
+// CreateStatement returns the CREATE INDEX statement of the index.
+func (i DBIndex) CreateStatement() string {
+	var b strings.Builder
+	b.WriteString("CREATE ")
+	if i.Unique {
+		b.WriteString("UNIQUE ")
+	}
+	b.WriteString("INDEX ")
+	if i.Concurrently {
+		b.WriteString("CONCURRENTLY ")
+	}
+	// No IF NOT EXISTS: an existing index of the same name must fail the
+	// create rather than be adopted with a different definition.
+	fmt.Fprintf(&b, "%s ON %s.%s USING %s (%s)",
+		QuoteIdentifier(i.Name), QuoteIdentifier(i.Schema), QuoteIdentifier(i.Table), i.Method, i.Expression)
+	if i.Where != "" {
+		fmt.Fprintf(&b, " WHERE %s", i.Where)
+	}
+	return b.String()
+}
+
+// CreateDBIndex creates the index through /$sql.
+func (c *AidboxHTTPClient) CreateDBIndex(ctx context.Context, index DBIndex) error {
+	_, err := c.ExecuteSQL(ctx, index.CreateStatement())
+	return err
+}
+
+// DropDBIndex drops the index through /$sql.
+func (c *AidboxHTTPClient) DropDBIndex(ctx context.Context, schema, name string, concurrently bool) error {
+	statement := "DROP INDEX "
+	if concurrently {
+		statement += "CONCURRENTLY "
+	}
+	statement += "IF EXISTS " + QuoteIdentifier(schema) + "." + QuoteIdentifier(name)
+
+	_, err := c.ExecuteSQL(ctx, statement)
+	return err
+}
+
+// GetDBIndex reads the index from pg_indexes. It returns nil when the index
+// does not exist.
+func (c *AidboxHTTPClient) GetDBIndex(ctx context.Context, schema, name string) (*DBIndexState, error) {
+	rows, err := c.ExecuteSQL(ctx, "SELECT tablename, indexdef FROM pg_indexes WHERE schemaname = ? AND indexname = ?", schema, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	table, _ := rows[0]["tablename"].(string)
+	definition, _ := rows[0]["indexdef"].(string)
+
+	state := &DBIndexState{
+		Table:      table,
+		Definition: definition,
+		Unique:     strings.HasPrefix(definition, "CREATE UNIQUE INDEX"),
+	}
+	state.Method, state.Expression, state.Where = parseIndexDefinition(definition)
+	return state, nil
+}
+
+// parseIndexDefinition splits an indexdef such as
+// "CREATE INDEX name ON public.table USING gin (expr) WHERE (pred)" into its
+// method, expression and predicate.
+func parseIndexDefinition(definition string) (method, expression, where string) {
+	_, rest, ok := strings.Cut(definition, " USING ")
+	if !ok {
+		return "", "", ""
+	}
+	method, rest, _ = strings.Cut(rest, " ")
+
+	// Find the parenthesis closing the expression list, skipping quoted text.
+	depth, inQuote := 0, false
+	for i, ch := range rest {
+		switch {
+		case ch == '\'':
+			inQuote = !inQuote
+		case inQuote:
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+			if depth == 0 {
+				expression = rest[1:i]
+				where = strings.TrimPrefix(strings.TrimSpace(rest[i+1:]), "WHERE ")
+				return method, expression, where
+			}
+		}
+	}
+	return method, "", ""
+}
+
+// NormalizeIndexExpression returns a comparison key for an index expression
+// or predicate, so that a configured expression can be compared with the
+// one Postgres reports. Outside of quoted text, whitespace, parentheses and
+// type casts are dropped and the text is lowercased, e.g. both
+// "(resource#>>'{name}')" and "((resource #>> '{name}'::text[]))" become
+// "resource#>>'{name}'". The key is only meant for equality checks.
+func NormalizeIndexExpression(expression string) string {
+	var b strings.Builder
+	for i := 0; i < len(expression); i++ {
+		ch := expression[i]
+		switch {
+		case ch == '\'' || ch == '"':
+			// Copy quoted text verbatim; doubled quotes are escapes and
+			// simply end and restart the quoted text.
+			end := strings.IndexByte(expression[i+1:], ch)
+			if end < 0 {
+				b.WriteString(expression[i:])
+				return b.String()
+			}
+			b.WriteString(expression[i : i+end+2])
+			i += end + 1
+		case ch == ':' && i+1 < len(expression) && expression[i+1] == ':':
+			i = skipIndexCast(expression, i+2) - 1
+		case ch == '(' || ch == ')' || ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+		default:
+			b.WriteByte(toLowerASCII(ch))
+		}
+	}
+	return b.String()
+}
+
+// castTypeWords are the words that may follow the first word of a
+// multi-word type name, as in "character varying" or "timestamp with time
+// zone".
+var castTypeWords = map[string]bool{"varying": true, "precision": true, "with": true, "without": true, "time": true, "zone": true}
+
+// skipIndexCast returns the index following the type name of a cast that
+// starts at i, e.g. "text[]", "character varying" or "\"char\"".
+func skipIndexCast(expression string, i int) int {
+	i = skipIndexCastWord(expression, i)
+	for {
+		word := i
+		for word < len(expression) && expression[word] == ' ' {
+			word++
+		}
+		end := skipIndexCastWord(expression, word)
+		if end == word || !castTypeWords[strings.ToLower(strings.TrimRight(expression[word:end], "[]"))] {
+			return i
+		}
+		i = end
+	}
+}
+
+// skipIndexCastWord returns the index following a word of a type name.
+func skipIndexCastWord(expression string, i int) int {
+	for i < len(expression) && expression[i] == ' ' {
+		i++
+	}
+	if i < len(expression) && expression[i] == '"' {
+		if end := strings.IndexByte(expression[i+1:], '"'); end >= 0 {
+			return i + end + 2
+		}
+	}
+	for i < len(expression) {
+		ch := toLowerASCII(expression[i])
+		if !(ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_' || ch == '.' || ch == '[' || ch == ']') {
+			break
+		}
+		i++
+	}
+	return i
+}
+
+func toLowerASCII(ch byte) byte {
+	if ch >= 'A' && ch <= 'Z' {
+		return ch + 'a' - 'A'
+	}
+	return ch
+}
+
+// IndexSuggestion is an index proposed by the Aidbox index-suggestion RPC.
+type IndexSuggestion struct {
+	IndexName string `json:"index-name"`
+	SQL       string `json:"sql"`
+}
+
+// SuggestIndex asks Aidbox which indexes would speed up searches by
+// searchParam on resourceType.
+func (c *AidboxHTTPClient) SuggestIndex(ctx context.Context, resourceType, searchParam string) ([]IndexSuggestion, error) {
+	var suggestions []IndexSuggestion
+	err := c.CallRPC(ctx, "aidbox.index/suggest-index", map[string]interface{}{
+		"resource-type": resourceType,
+		"search-param":  searchParam,
+	}, &suggestions)
+	return suggestions, err
+}
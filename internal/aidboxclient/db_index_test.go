@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"testing"
+)
+
+func TestDBIndex_CreateStatement(t *testing.T) {
+	index := DBIndex{
+		Schema:       "public",
+		Name:         "patient_family",
+		Table:        "patient",
+		Method:       "btree",
+		Expression:   "(resource#>>'{name,0,family}')",
+		Unique:       true,
+		Where:        "resource->>'active' = 'true'",
+		Concurrently: true,
+	}
+
+	expected := `CREATE UNIQUE INDEX CONCURRENTLY "patient_family" ON "public"."patient" USING btree ((resource#>>'{name,0,family}')) WHERE resource->>'active' = 'true'`
+	if got := index.CreateStatement(); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestParseIndexDefinition(t *testing.T) {
+	testCases := map[string]struct {
+		definition string
+		method     string
+		expression string
+		where      string
+	}{
+		"expression": {
+			definition: `CREATE INDEX patient_family ON public.patient USING btree (((resource #>> '{name,0,family}'::text[])))`,
+			method:     "btree",
+			expression: `((resource #>> '{name,0,family}'::text[]))`,
+		},
+		"gin with predicate": {
+			definition: `CREATE INDEX obs_gin ON public.observation USING gin (resource jsonb_path_ops) WHERE ((resource ->> 'status'::text) = 'final)'::text)`,
+			method:     "gin",
+			expression: "resource jsonb_path_ops",
+			where:      `((resource ->> 'status'::text) = 'final)'::text)`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			method, expression, where := parseIndexDefinition(tc.definition)
+			if method != tc.method || expression != tc.expression || where != tc.where {
+				t.Errorf("expected (%q, %q, %q), got (%q, %q, %q)", tc.method, tc.expression, tc.where, method, expression, where)
+			}
+		})
+	}
+}
+
+func TestNormalizeIndexExpression(t *testing.T) {
+	testCases := map[string]struct {
+		configured string
+		reported   string
+		equal      bool
+	}{
+		"expression": {
+			configured: `(resource#>>'{name,0,family}')`,
+			reported:   `((resource #>> '{name,0,family}'::text[]))`,
+			equal:      true,
+		},
+		"operator class": {
+			configured: "resource jsonb_path_ops",
+			reported:   "resource jsonb_path_ops",
+			equal:      true,
+		},
+		"predicate": {
+			configured: "resource->>'status' = 'final'",
+			reported:   `((resource ->> 'status'::text) = 'final'::text)`,
+			equal:      true,
+		},
+		"multi-word cast": {
+			configured: "(resource->>'code')::character varying IS NOT NULL",
+			reported:   `(((resource ->> 'code'::text))::character varying IS NOT NULL)`,
+			equal:      true,
+		},
+		"case outside quotes": {
+			configured: "LOWER(resource->>'name')",
+			reported:   "lower((resource ->> 'name'::text))",
+			equal:      true,
+		},
+		"changed path": {
+			configured: `(resource#>>'{name,0,family}')`,
+			reported:   `((resource #>> '{name,0,given}'::text[]))`,
+		},
+		"changed literal": {
+			configured: "resource->>'status' = 'final'",
+			reported:   `((resource ->> 'status'::text) = 'amended'::text)`,
+		},
+		"changed literal case": {
+			configured: "resource->>'status' = 'final'",
+			reported:   `((resource ->> 'status'::text) = 'FINAL'::text)`,
+		},
+		"spaces in literal": {
+			configured: "resource->>'name' = 'a b'",
+			reported:   `((resource ->> 'name'::text) = 'ab'::text)`,
+		},
+		"dropped predicate": {
+			configured: "resource->>'status' = 'final'",
+			reported:   "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			configured, reported := NormalizeIndexExpression(tc.configured), NormalizeIndexExpression(tc.reported)
+			if (configured == reported) != tc.equal {
+				t.Errorf("expected equal %v, got %q and %q", tc.equal, configured, reported)
+			}
+		})
+	}
+}
+
+func TestParseIndexDefinitionDrift(t *testing.T) {
+	_, expression, where := parseIndexDefinition(`CREATE INDEX obs_status ON public.observation USING btree (((resource #>> '{code,text}'::text[]))) WHERE ((resource ->> 'status'::text) = 'amended'::text)`)
+	if NormalizeIndexExpression(expression) != NormalizeIndexExpression(`(resource#>>'{code,text}')`) {
+		t.Errorf("expected expression %q to match the configured one", expression)
+	}
+	if NormalizeIndexExpression(where) == NormalizeIndexExpression("resource->>'status' = 'final'") {
+		t.Errorf("expected predicate %q to drift from the configured one", where)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// NetworkErrorKind classifies failures that happen before any HTTP response
+// is received.
+type NetworkErrorKind string
+
+const (
+	NetworkErrorDNS               NetworkErrorKind = "dns"
+	NetworkErrorTLS               NetworkErrorKind = "tls"
+	NetworkErrorTimeout           NetworkErrorKind = "timeout"
+	NetworkErrorConnectionRefused NetworkErrorKind = "connection_refused"
+	NetworkErrorUnknown           NetworkErrorKind = "unknown"
+)
+
+// NetworkError is returned when an Aidbox endpoint could not be reached.
+type NetworkError struct {
+	Kind     NetworkErrorKind
+	Endpoint string
+	Err      error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("API call failed (%s) calling %s: %s", e.Kind, e.Endpoint, Redact(e.Err.Error()))
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is returned when an Aidbox endpoint responds with a non-success
+// status code. Body is already redacted.
+type APIError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API response error: %s; Body: %s", e.Status, e.Body)
+}
+
+// IsNotFound reports whether err is an APIError with a 404 or 410 status.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusGone
+}
+
+func newNetworkError(endpoint string, err error) *NetworkError {
+	return &NetworkError{
+		Kind:     classifyNetworkError(err),
+		Endpoint: endpoint,
+		Err:      err,
+	}
+}
+
+func classifyNetworkError(err error) NetworkErrorKind {
+	var (
+		dnsErr          *net.DNSError
+		netErr          net.Error
+		recordHeaderErr tls.RecordHeaderError
+		certVerifyErr   *tls.CertificateVerificationError
+		unknownAuthErr  x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		certInvalidErr  x509.CertificateInvalidError
+	)
+
+	switch {
+	case errors.As(err, &dnsErr) && !dnsErr.IsTimeout:
+		return NetworkErrorDNS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return NetworkErrorTimeout
+	case errors.As(err, &certVerifyErr), errors.As(err, &unknownAuthErr), errors.As(err, &hostnameErr),
+		errors.As(err, &certInvalidErr), errors.As(err, &recordHeaderErr):
+		return NetworkErrorTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return NetworkErrorConnectionRefused
+	default:
+		return NetworkErrorUnknown
+	}
+}
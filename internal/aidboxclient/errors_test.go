@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// unreachableEndpoint returns the URL of a local port that nothing listens on.
+func unreachableEndpoint(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to open listener: %s", err)
+	}
+	addr := listener.Addr().String()
+	if err := listener.Close(); err != nil {
+		t.Fatalf("failed to close listener: %s", err)
+	}
+
+	return "http://" + addr + "/rpc"
+}
+
+func TestMakeAPICall_ConnectionRefused(t *testing.T) {
+	client := NewClient(unreachableEndpoint(t), "token", nil)
+
+	_, err := client.GetLicense(context.Background(), "lic-1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected *NetworkError, got %T: %s", err, err)
+	}
+	if netErr.Kind != NetworkErrorConnectionRefused {
+		t.Errorf("expected kind %q, got %q", NetworkErrorConnectionRefused, netErr.Kind)
+	}
+}
+
+func TestMakeAPICall_DeleteConnectionRefused(t *testing.T) {
+	client := NewClient(unreachableEndpoint(t), "token", nil)
+
+	var netErr *NetworkError
+	if err := client.DeleteLicense(context.Background(), "lic-1"); !errors.As(err, &netErr) {
+		t.Fatalf("expected *NetworkError, got %T: %v", err, err)
+	}
+}
+
+func TestMakeAPICall_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	client := NewClient(srv.URL, "token", &http.Client{Timeout: 50 * time.Millisecond})
+
+	_, err := client.CreateLicense(context.Background(), "test", "aidbox", "development")
+
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected *NetworkError, got %T: %v", err, err)
+	}
+	if netErr.Kind != NetworkErrorTimeout {
+		t.Errorf("expected kind %q, got %q", NetworkErrorTimeout, netErr.Kind)
+	}
+}
+
+func TestMakeAPICall_UntrustedCertificate(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "token", nil)
+
+	_, err := client.GetLicense(context.Background(), "lic-1")
+
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		t.Fatalf("expected *NetworkError, got %T: %v", err, err)
+	}
+	if netErr.Kind != NetworkErrorTLS {
+		t.Errorf("expected kind %q, got %q", NetworkErrorTLS, netErr.Kind)
+	}
+}
+
+func TestMakeAPICall_StatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("message: not found"))
+	}))
+	defer srv.Close()
+
+	err := NewClient(srv.URL, "token", nil).DeleteLicense(context.Background(), "lic-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if !IsNotFound(err) {
+		t.Errorf("expected IsNotFound to be true for status %d", apiErr.StatusCode)
+	}
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected NetworkErrorKind
+	}{
+		"dns": {
+			err:      &url.Error{Op: "Post", URL: "https://aidbox.invalid", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "aidbox.invalid", IsNotFound: true}}},
+			expected: NetworkErrorDNS,
+		},
+		"dns timeout": {
+			err:      &net.DNSError{Err: "i/o timeout", Name: "aidbox.app", IsTimeout: true},
+			expected: NetworkErrorTimeout,
+		},
+		"context deadline": {
+			err:      fmt.Errorf("wrapped: %w", context.DeadlineExceeded),
+			expected: NetworkErrorTimeout,
+		},
+		"other": {
+			err:      errors.New("boom"),
+			expected: NetworkErrorUnknown,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := classifyNetworkError(tc.err); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+)
+
+// FHIRPackage is a FHIR NPM package installed on the instance.
+type FHIRPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// parameters is a FHIR Parameters resource with string parameters.
+type parameters struct {
+	ResourceType string      `json:"resourceType"`
+	Parameter    []parameter `json:"parameter"`
+}
+
+type parameter struct {
+	Name        string `json:"name"`
+	ValueString string `json:"valueString"`
+}
+
+func stringParameters(name string, values ...string) parameters {
+	p := parameters{ResourceType: "Parameters"}
+	for _, v := range values {
+		p.Parameter = append(p.Parameter, parameter{Name: name, ValueString: v})
+	}
+	return p
+}
+
+// InstallFHIRPackage starts the installation of a package from the
+// registry, e.g. "hl7.fhir.us.core@6.1.0". It returns the status URL of the
+// installation, or "" when it completed synchronously.
+func (c *AidboxHTTPClient) InstallFHIRPackage(ctx context.Context, pkg string) (string, error) {
+	payload, err := json.Marshal(stringParameters("package", pkg))
+	if err != nil {
+		return "", fmt.Errorf("failed to create JSON request body: %w", err)
+	}
+
+	resp, err := c.doInstanceWithHeader(ctx, "POST", "/fhir/$fhir-package-install", asyncHeader("application/json"), bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	return asyncStatusURL(resp), nil
+}
+
+// UploadFHIRPackage uploads and installs a package archive (.tgz). It
+// returns the status URL of the installation, or "" when it completed
+// synchronously.
+func (c *AidboxHTTPClient) UploadFHIRPackage(ctx context.Context, filename string, content io.Reader) (string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return "", fmt.Errorf("failed to read package %s: %w", filename, err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to create upload request: %w", err)
+	}
+
+	resp, err := c.doInstanceWithHeader(ctx, "POST", "/$upload-fhir-npm-packages", asyncHeader(writer.FormDataContentType()), &body)
+	if err != nil {
+		return "", err
+	}
+	return asyncStatusURL(resp), nil
+}
+
+// GetFHIRPackage returns the installed package with the given name, or nil
+// when it is not installed.
+func (c *AidboxHTTPClient) GetFHIRPackage(ctx context.Context, name string) (*FHIRPackage, error) {
+	var bundle struct {
+		Entry []struct {
+			Resource FHIRPackage `json:"resource"`
+		} `json:"entry"`
+	}
+	if err := c.instanceJSON(ctx, "GET", "/fhir/FHIRPackage?name="+url.QueryEscape(name), nil, &bundle); err != nil {
+		return nil, err
+	}
+
+	for _, e := range bundle.Entry {
+		if e.Resource.Name == name {
+			pkg := e.Resource
+			return &pkg, nil
+		}
+	}
+	return nil, nil
+}
+
+// UninstallFHIRPackage removes an installed package. Uninstalling a package
+// that is not installed is not an error.
+func (c *AidboxHTTPClient) UninstallFHIRPackage(ctx context.Context, name string) error {
+	err := c.instanceJSON(ctx, "POST", "/fhir/$fhir-package-uninstall", stringParameters("name", name), nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
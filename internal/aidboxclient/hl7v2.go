@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Hl7v2Config configures how HL7v2 messages posted as Hl7v2Message
+// resources are parsed and which Mapping turns them into FHIR resources.
+type Hl7v2Config struct {
+	ResourceType string     `json:"resourceType"`
+	ID           string     `json:"id,omitempty"`
+	IsStrict     bool       `json:"isStrict"`
+	Mapping      *Reference `json:"mapping,omitempty"`
+}
+
+// Mapping is a JUTE template that transforms its input, e.g. a parsed HL7v2
+// message, into a FHIR transaction Bundle.
+type Mapping struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// Hl7v2ParseResult is the result of the hl7v2.core/parse RPC.
+type Hl7v2ParseResult struct {
+	Parsed json.RawMessage `json:"parsed"`
+	Errors json.RawMessage `json:"errors"`
+}
+
+// ErrorMessages returns the parse errors. Aidbox reports them either as
+// strings or as objects with a message.
+func (r Hl7v2ParseResult) ErrorMessages() []string {
+	var raw []json.RawMessage
+	if json.Unmarshal(r.Errors, &raw) != nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(raw))
+	for _, e := range raw {
+		var message string
+		if json.Unmarshal(e, &message) == nil {
+			messages = append(messages, message)
+			continue
+		}
+		var obj struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(e, &obj) == nil && obj.Message != "" {
+			messages = append(messages, obj.Message)
+			continue
+		}
+		messages = append(messages, string(e))
+	}
+	return messages
+}
+
+// normalizeHl7v2 separates segments with carriage returns, as HL7v2
+// requires, whatever line endings the message was written with.
+func normalizeHl7v2(message string) string {
+	message = strings.ReplaceAll(message, "\r\n", "\r")
+	message = strings.ReplaceAll(message, "\n", "\r")
+	return strings.Trim(message, "\r")
+}
+
+// ParseHl7v2 parses an HL7v2 message without storing it. In strict mode
+// Aidbox rejects messages that do not follow the message structure.
+func (c *AidboxHTTPClient) ParseHl7v2(ctx context.Context, message string, strict bool) (Hl7v2ParseResult, error) {
+	var result Hl7v2ParseResult
+	err := c.CallRPC(ctx, "hl7v2.core/parse", map[string]interface{}{
+		"message": normalizeHl7v2(message),
+		"strict":  strict,
+	}, &result)
+	return result, err
+}
+
+// DebugMapping applies a mapping body to an input without saving the
+// mapping, through /Mapping/$debug.
+func (c *AidboxHTTPClient) DebugMapping(ctx context.Context, body, input json.RawMessage) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.instanceJSON(ctx, "POST", "/Mapping/$debug", map[string]interface{}{
+		"mapping": map[string]interface{}{"body": body},
+		"scope":   input,
+	}, &result)
+	return result, err
+}
+
+// ApplyMapping applies a saved mapping to an input without executing the
+// resulting transaction, through /Mapping/<id>/$debug.
+func (c *AidboxHTTPClient) ApplyMapping(ctx context.Context, id string, input json.RawMessage) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.instanceJSON(ctx, "POST", resourcePath("Mapping", id)+"/$debug", input, &result)
+	return result, err
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseHl7v2(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				Message string `json:"message"`
+				Strict  bool   `json:"strict"`
+			} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if r.URL.Path != "/rpc" || req.Method != "hl7v2.core/parse" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, req.Method)
+		}
+		if want := "MSH|^~\\&|A\rPID|1"; req.Params.Message != want || !req.Params.Strict {
+			t.Errorf("expected message %q in strict mode, got %q, %v", want, req.Params.Message, req.Params.Strict)
+		}
+		w.Write([]byte(`{"result": {"parsed": {"MSH": {}}, "errors": ["unknown segment ZZZ", {"message": "missing EVN"}]}}`))
+	})
+
+	result, err := client.ParseHl7v2(context.Background(), "MSH|^~\\&|A\r\nPID|1\n", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result.Parsed) != `{"MSH": {}}` {
+		t.Errorf("unexpected parsed message %s", result.Parsed)
+	}
+	if got, want := result.ErrorMessages(), []string{"unknown segment ZZZ", "missing EVN"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected errors %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import "context"
+
+// Statuses of a hosted instance. Instances are provisioning until they are
+// ready or failed.
+const (
+	HostedInstanceReady  = "ready"
+	HostedInstanceFailed = "failed"
+)
+
+// HostedInstance is an Aidbox instance hosted by the portal for a license.
+type HostedInstance struct {
+	ID                string `yaml:"id"`
+	Name              string `yaml:"name"`
+	License           string `yaml:"license"`
+	Region            string `yaml:"region,omitempty"`
+	Status            string `yaml:"status"`
+	Error             string `yaml:"error,omitempty"`
+	URL               string `yaml:"url,omitempty"`
+	AdminClientID     string `yaml:"admin-client-id,omitempty"`
+	AdminClientSecret string `yaml:"admin-client-secret,omitempty"`
+}
+
+// CreateHostedInstance requests a hosted instance for a license. The
+// instance is provisioned asynchronously.
+func (c *AidboxHTTPClient) CreateHostedInstance(ctx context.Context, licenseID, name, region string) (HostedInstance, error) {
+	params := map[string]interface{}{
+		"license": licenseID,
+		"name":    name,
+	}
+	if region != "" {
+		params["region"] = region
+	}
+
+	var resp struct {
+		Result struct {
+			Instance HostedInstance `yaml:"instance"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/create-hosted-instance", params, &resp)
+	return resp.Result.Instance, err
+}
+
+// GetHostedInstance returns nil when the instance does not exist.
+func (c *AidboxHTTPClient) GetHostedInstance(ctx context.Context, id string) (*HostedInstance, error) {
+	var resp struct {
+		Result struct {
+			Instance *HostedInstance `yaml:"instance"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/get-hosted-instance", map[string]interface{}{"id": id}, &resp)
+	if err != nil {
+		gone, err := c.isPortalObjectGone(ctx, err)
+		if gone {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Result.Instance, nil
+}
+
+// DeleteHostedInstance shuts down a hosted instance and deletes its data.
+func (c *AidboxHTTPClient) DeleteHostedInstance(ctx context.Context, id string) error {
+	err := c.portalCall(ctx, "portal.portal/delete-hosted-instance", map[string]interface{}{"id": id}, nil)
+	if err != nil {
+		_, err = c.isPortalObjectGone(ctx, err)
+	}
+	return err
+}
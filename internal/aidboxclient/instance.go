@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ErrInstanceNotConfigured is returned by instance API calls when no
+// instance URL has been configured.
+var ErrInstanceNotConfigured = errors.New("no Aidbox instance configured: please set 'instance_url', 'client_id' and 'client_secret'")
+
+// instanceResponse is a fully read response of the instance API.
+type instanceResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// doInstance performs an authenticated request against the instance API.
+// Non-2xx responses are returned as *APIError.
+func (c *AidboxHTTPClient) doInstance(ctx context.Context, method, path, contentType string, body io.Reader) (*instanceResponse, error) {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	return c.doInstanceWithHeader(ctx, method, path, header, body)
+}
+
+// doInstanceWithHeader is doInstance with additional request headers, e.g.
+// "Prefer: respond-async".
+func (c *AidboxHTTPClient) doInstanceWithHeader(ctx context.Context, method, path string, header http.Header, body io.Reader) (*instanceResponse, error) {
+	if c.InstanceURL == "" {
+		return nil, ErrInstanceNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.instanceEndpoint(path), body)
+	if err != nil {
+		tflog.Error(ctx, "Failed to create HTTP request", map[string]interface{}{"error": err.Error()})
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(c.ClientID, c.ClientSecret)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		netErr := newNetworkError(c.InstanceURL, err)
+		tflog.Error(ctx, "API call failed", map[string]interface{}{"error": netErr.Error(), "kind": string(netErr.Kind)})
+		return nil, netErr
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		tflog.Error(ctx, "Failed to read response body", map[string]interface{}{"error": err.Error()})
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		redactedBody := Redact(string(bodyBytes))
+		tflog.Error(ctx, "API response error", map[string]interface{}{
+			"status": resp.Status,
+			"body":   redactedBody,
+		})
+		return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status, Body: redactedBody}
+	}
+
+	return &instanceResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: bodyBytes}, nil
+}
+
+// instanceEndpoint returns the URL of a path, or of an absolute URL naming
+// the instance, on the configured instance.
+func (c *AidboxHTTPClient) instanceEndpoint(path string) string {
+	return strings.TrimSuffix(c.InstanceURL, "/") + "/" + strings.TrimPrefix(c.instancePath(path), "/")
+}
+
+// instancePath returns the path of a URL on the instance. Status URLs of
+// asynchronous requests are absolute and, behind a proxy, may name the
+// internal address of the instance, e.g. "http://localhost:8080/...": only
+// their path and query are used. Other paths are returned unchanged.
+func (c *AidboxHTTPClient) instancePath(p string) string {
+	u, err := url.Parse(p)
+	if err != nil || !u.IsAbs() {
+		return p
+	}
+
+	path := u.EscapedPath()
+	if base, err := url.Parse(c.InstanceURL); err == nil {
+		if basePath := strings.TrimSuffix(base.EscapedPath(), "/"); basePath != "" && strings.HasPrefix(path, basePath+"/") {
+			path = strings.TrimPrefix(path, basePath)
+		}
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// instanceJSON sends in as a JSON body (when not nil) and decodes the
+// response into out (when not nil).
+func (c *AidboxHTTPClient) instanceJSON(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	contentType := ""
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to create JSON request body: %w", err)
+		}
+		body = bytes.NewReader(payload)
+		contentType = "application/json"
+	}
+
+	resp, err := c.doInstance(ctx, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+
+	if out == nil || len(bytes.TrimSpace(resp.Body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body, out); err != nil {
+		tflog.Error(ctx, "Failed to parse JSON response", map[string]interface{}{"error": err.Error(), "body": Redact(string(resp.Body))})
+		return fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return nil
+}
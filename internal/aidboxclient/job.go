@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Engines of an AidboxJob action.
+const (
+	JobEngineSQL  = "sql"
+	JobEngineRPC  = "rpc"
+	JobEngineHTTP = "http"
+)
+
+// AidboxJob is a task run by the Aidbox scheduler, either every Every
+// seconds or daily at At ("HH:MM", UTC). Type is always "periodic".
+type AidboxJob struct {
+	ResourceType string    `json:"resourceType"`
+	ID           string    `json:"id,omitempty"`
+	Type         string    `json:"type"`
+	Every        int64     `json:"every,omitempty"`
+	At           string    `json:"at,omitempty"`
+	Action       JobAction `json:"action"`
+}
+
+// JobAction is what an AidboxJob runs.
+type JobAction struct {
+	Engine string         `json:"engine"`
+	Query  string         `json:"query,omitempty"`
+	RPC    *JobRPCAction  `json:"rpc,omitempty"`
+	HTTP   *JobHTTPAction `json:"http,omitempty"`
+}
+
+// JobRPCAction calls an RPC method of the instance.
+type JobRPCAction struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// JobHTTPAction sends an HTTP request.
+type JobHTTPAction struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// AidboxJobStatus is the state of the last run of an AidboxJob. It has the
+// ID of its job and does not exist until the job first runs.
+type AidboxJobStatus struct {
+	ID        string          `json:"id"`
+	Status    string          `json:"status"`
+	Start     string          `json:"start"`
+	Stop      string          `json:"stop"`
+	NextStart string          `json:"next-start"`
+	Error     json.RawMessage `json:"error"`
+}
+
+// ErrorMessage returns the error of the last run, or "" when it succeeded.
+// Aidbox reports errors either as a string or as an object with a message.
+func (s AidboxJobStatus) ErrorMessage() string {
+	if len(s.Error) == 0 || string(s.Error) == "null" {
+		return ""
+	}
+
+	var message string
+	if json.Unmarshal(s.Error, &message) == nil {
+		return message
+	}
+	var obj struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(s.Error, &obj) == nil && obj.Message != "" {
+		return obj.Message
+	}
+	return string(s.Error)
+}
+
+// GetJobStatus returns the status of the last run of a job, or nil when the
+// job has not run yet.
+func (c *AidboxHTTPClient) GetJobStatus(ctx context.Context, jobID string) (*AidboxJobStatus, error) {
+	var status AidboxJobStatus
+	err := c.GetResource(ctx, "AidboxJobStatus", jobID, &status)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
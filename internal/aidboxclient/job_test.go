@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAidboxJobStatusErrorMessage(t *testing.T) {
+	testCases := map[string]string{
+		``:                                  "",
+		`null`:                              "",
+		`"relation \"tmp\" does not exist"`: `relation "tmp" does not exist`,
+		`{"message": "timeout"}`:            "timeout",
+		`{"code": 500}`:                     `{"code": 500}`,
+	}
+	for raw, want := range testCases {
+		status := AidboxJobStatus{Error: []byte(raw)}
+		if got := status.ErrorMessage(); got != want {
+			t.Errorf("%s: expected %q, got %q", raw, want, got)
+		}
+	}
+}
+
+func TestGetJobStatusNotRun(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/AidboxJobStatus/cleanup" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	status, err := client.GetJobStatus(context.Background(), "cleanup")
+	if err != nil || status != nil {
+		t.Errorf("expected no status and no error, got %v, %v", status, err)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// redactedValue replaces every secret value in logs and error messages.
+const redactedValue = "***"
+
+// sensitiveKeyPattern matches keys whose values must never be logged.
+const sensitiveKeyPattern = `[\w.-]*(?:token|jwt|secret|password|authorization)[\w.-]*`
+
+var redactRules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// JSON: "client_secret": "value"
+	{regexp.MustCompile(`(?i)("` + sensitiveKeyPattern + `"\s*:\s*)"(?:[^"\\]|\\.)*"`), `${1}"` + redactedValue + `"`},
+	// YAML: token: value
+	{regexp.MustCompile(`(?im)^(\s*(?:-\s+)?` + sensitiveKeyPattern + `\s*:[ \t]+)[^\s#][^\n]*$`), `${1}` + redactedValue},
+	// Query strings and form bodies: ?token=value&...
+	{regexp.MustCompile(`(?i)(` + sensitiveKeyPattern + `=)[^&\s"]+`), `${1}` + redactedValue},
+	// Authorization header values embedded in text.
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), `${1} ` + redactedValue},
+	// Bare JWTs, e.g. license tokens nested in unrelated fields.
+	{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redactedValue},
+}
+
+var sensitiveHeaderRe = regexp.MustCompile(`(?i)^(?:` + sensitiveKeyPattern + `|cookie|set-cookie|x-api-key|proxy-authorization)$`)
+
+// Redact masks the values of token, jwt, secret, password and authorization
+// fields in YAML, JSON, query string and header formatted text.
+func Redact(s string) string {
+	for _, rule := range redactRules {
+		s = rule.re.ReplaceAllString(s, rule.repl)
+	}
+	return s
+}
+
+// redactHeaders returns the headers as a log-friendly map with sensitive
+// values masked.
+func redactHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string, len(h))
+	for name, values := range h {
+		if sensitiveHeaderRe.MatchString(name) {
+			headers[name] = redactedValue
+			continue
+		}
+		headers[name] = Redact(strings.Join(values, ", "))
+	}
+	return headers
+}
+
+// bodyLoggingEnabled reports whether request and response bodies should be
+// logged. Bodies are only captured at TRACE verbosity to avoid buffering them
+// when nobody is going to read the output.
+func bodyLoggingEnabled() bool {
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+
+	switch strings.ToUpper(level) {
+	case "TRACE", "JSON":
+		return true
+	default:
+		return false
+	}
+}
+
+// loggingTransport logs every HTTP exchange through tflog with secrets masked.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+// NewLoggingTransport wraps next so that requests and responses are logged
+// with sensitive values redacted. A nil next uses http.DefaultTransport.
+func NewLoggingTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	logBodies := bodyLoggingEnabled()
+
+	reqFields := map[string]interface{}{
+		"http_method": req.Method,
+		"http_url":    Redact(req.URL.String()),
+	}
+	tflog.Debug(ctx, "Sending HTTP request", reqFields)
+
+	if logBodies {
+		traceFields := map[string]interface{}{
+			"http_headers": redactHeaders(req.Header),
+		}
+		if req.Body != nil && req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				bodyBytes, _ := io.ReadAll(body)
+				body.Close()
+				traceFields["http_body"] = Redact(string(bodyBytes))
+			}
+		}
+		tflog.Trace(ctx, "HTTP request details", traceFields)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		tflog.Debug(ctx, "HTTP request failed", map[string]interface{}{
+			"http_method":      req.Method,
+			"http_url":         Redact(req.URL.String()),
+			"http_duration_ms": duration.Milliseconds(),
+			"error":            Redact(err.Error()),
+		})
+		return resp, err
+	}
+
+	tflog.Debug(ctx, "Received HTTP response", map[string]interface{}{
+		"http_method":      req.Method,
+		"http_url":         Redact(req.URL.String()),
+		"http_status":      resp.StatusCode,
+		"http_duration_ms": duration.Milliseconds(),
+	})
+
+	if logBodies {
+		traceFields := map[string]interface{}{
+			"http_headers": redactHeaders(resp.Header),
+		}
+		if resp.Body != nil {
+			bodyBytes, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			// Hand the buffered body back to the caller, including any read error.
+			resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(bodyBytes), errReader{readErr}))
+			traceFields["http_body"] = Redact(string(bodyBytes))
+		}
+		tflog.Trace(ctx, "HTTP response details", traceFields)
+	}
+
+	return resp, nil
+}
+
+// errReader returns err on every read, or io.EOF when err is nil.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
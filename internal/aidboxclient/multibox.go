@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Box is a tenant box of a Multibox server.
+type Box struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description,omitempty"`
+	FHIRVersion string            `json:"fhir-version,omitempty"`
+	BoxURL      string            `json:"box-url,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+}
+
+// CreateBox creates a box through the multibox/create-box RPC.
+func (c *AidboxHTTPClient) CreateBox(ctx context.Context, box Box) (Box, error) {
+	var result Box
+	if err := c.CallRPC(ctx, "multibox/create-box", box, &result); err != nil {
+		return Box{}, err
+	}
+	if result.ID == "" {
+		result.ID = box.ID
+	}
+	return result, nil
+}
+
+// GetBox fetches a box of the Multibox server.
+func (c *AidboxHTTPClient) GetBox(ctx context.Context, id string) (Box, error) {
+	var box Box
+	err := c.GetResource(ctx, "Box", id, &box)
+	return box, err
+}
+
+// DeleteBox deletes a box and its database through the multibox/delete-box
+// RPC.
+func (c *AidboxHTTPClient) DeleteBox(ctx context.Context, id string) error {
+	return c.CallRPC(ctx, "multibox/delete-box", map[string]string{"id": id}, nil)
+}
+
+// BoxURL returns the URL of a box. Multibox serves boxes on subdomains of
+// the server, e.g. https://tenant.multibox.example.org.
+func (c *AidboxHTTPClient) BoxURL(box Box) (string, error) {
+	if box.BoxURL != "" {
+		return box.BoxURL, nil
+	}
+
+	u, err := url.Parse(c.InstanceURL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid instance URL %q", c.InstanceURL)
+	}
+	return fmt.Sprintf("%s://%s.%s", u.Scheme, box.ID, u.Host), nil
+}
@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCreateBox(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params Box    `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if r.URL.Path != "/rpc" || req.Method != "multibox/create-box" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, req.Method)
+		}
+		if req.Params.ID != "tenant-a" || req.Params.Env["BOX_ROOT_CLIENT_ID"] != "root" {
+			t.Errorf("unexpected params %+v", req.Params)
+		}
+		_, _ = w.Write([]byte(`{"result": {"id": "tenant-a", "fhir-version": "4.0.1"}}`))
+	})
+
+	box, err := client.CreateBox(context.Background(), Box{ID: "tenant-a", Env: map[string]string{"BOX_ROOT_CLIENT_ID": "root"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The box URL is derived from the server URL when it is not returned.
+	boxURL, err := client.BoxURL(box)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := strings.Replace(client.InstanceURL, "://", "://tenant-a.", 1)
+	if boxURL != expected {
+		t.Errorf("expected %q, got %q", expected, boxURL)
+	}
+
+	box.BoxURL = "https://tenant-a.example.org"
+	if boxURL, _ := client.BoxURL(box); boxURL != box.BoxURL {
+		t.Errorf("expected the returned box URL, got %q", boxURL)
+	}
+}
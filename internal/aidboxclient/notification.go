@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// emailProviderConfigID is the ID of the AidboxConfig that holds the email
+// providers, keyed by name. Aidbox sends mail with the "default" provider.
+const emailProviderConfigID = "provider"
+
+// NotificationTemplate is the subject and body of an email Aidbox sends,
+// e.g. for password resets. Both are Handlebars templates.
+type NotificationTemplate struct {
+	ResourceType string `json:"resourceType"`
+	ID           string `json:"id,omitempty"`
+	Subject      string `json:"subject"`
+	Template     string `json:"template"`
+}
+
+// EmailProvider is a provider of the AidboxConfig "provider". Only the
+// fields of its type are set.
+type EmailProvider struct {
+	Type     string `json:"type"`
+	From     string `json:"from"`
+	Host     string `json:"host,omitempty"`
+	Port     int64  `json:"port,omitempty"`
+	TLS      *bool  `json:"tls,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	URL      string `json:"url,omitempty"`
+	APIKey   string `json:"api-key,omitempty"`
+}
+
+// GetEmailProvider returns the email provider with the given name, or nil
+// when it does not exist.
+func (c *AidboxHTTPClient) GetEmailProvider(ctx context.Context, name string) (*EmailProvider, error) {
+	var config struct {
+		Provider map[string]EmailProvider `json:"provider"`
+	}
+	err := c.GetResource(ctx, "AidboxConfig", emailProviderConfigID, &config)
+	if IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider, ok := config.Provider[name]
+	if !ok {
+		return nil, nil
+	}
+	return &provider, nil
+}
+
+// PutEmailProvider creates or replaces an email provider. The other
+// providers are left untouched.
+func (c *AidboxHTTPClient) PutEmailProvider(ctx context.Context, name string, provider EmailProvider) error {
+	value, err := emailProviderPatch(provider)
+	if err != nil {
+		return err
+	}
+
+	err = c.patchEmailProviders(ctx, map[string]interface{}{name: value})
+	if IsNotFound(err) {
+		return c.PutResource(ctx, "AidboxConfig", emailProviderConfigID, map[string]interface{}{
+			"resourceType": "AidboxConfig",
+			"id":           emailProviderConfigID,
+			"provider":     map[string]EmailProvider{name: provider},
+		}, nil)
+	}
+	return err
+}
+
+// emailProviderPatch returns the merge patch value of a provider. Fields
+// that are not set are null, so that fields of a previous type are removed.
+func emailProviderPatch(provider EmailProvider) (map[string]interface{}, error) {
+	b, err := json.Marshal(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JSON request body: %w", err)
+	}
+	var value map[string]interface{}
+	if err := json.Unmarshal(b, &value); err != nil {
+		return nil, fmt.Errorf("failed to create JSON request body: %w", err)
+	}
+
+	for _, field := range []string{"host", "port", "tls", "username", "password", "url", "api-key"} {
+		if _, ok := value[field]; !ok {
+			value[field] = nil
+		}
+	}
+	return value, nil
+}
+
+// DeleteEmailProvider removes an email provider. Removing a provider that
+// does not exist is not an error.
+func (c *AidboxHTTPClient) DeleteEmailProvider(ctx context.Context, name string) error {
+	err := c.patchEmailProviders(ctx, map[string]interface{}{name: nil})
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// patchEmailProviders applies a JSON merge patch to the providers of the
+// AidboxConfig; null values remove providers.
+func (c *AidboxHTTPClient) patchEmailProviders(ctx context.Context, providers map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{"provider": providers})
+	if err != nil {
+		return fmt.Errorf("failed to create JSON request body: %w", err)
+	}
+	return c.instanceJSON(ctx, "PATCH", resourcePath("AidboxConfig", emailProviderConfigID), json.RawMessage(patch), nil)
+}
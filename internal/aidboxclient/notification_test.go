@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestPutEmailProvider(t *testing.T) {
+	var requests []string
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case "PATCH":
+			var patch map[string]map[string]map[string]interface{}
+			if err := json.Unmarshal(body, &patch); err != nil {
+				t.Fatal(err)
+			}
+			smtp := patch["provider"]["default"]
+			if smtp["host"] != "smtp.example.org" || smtp["api-key"] != nil {
+				t.Errorf("unexpected patch %s", body)
+			}
+			if _, ok := smtp["api-key"]; !ok {
+				t.Error("expected unset fields to be removed")
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT":
+			_, _ = w.Write(body)
+		}
+	})
+
+	err := client.PutEmailProvider(context.Background(), "default", EmailProvider{Type: "smtp", From: "noreply@example.org", Host: "smtp.example.org", Port: 587})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The config is created when it does not exist yet.
+	if len(requests) != 2 || requests[0] != "PATCH /AidboxConfig/provider" || requests[1] != "PUT /AidboxConfig/provider" {
+		t.Errorf("unexpected requests %v", requests)
+	}
+}
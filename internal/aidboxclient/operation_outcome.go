@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// OperationOutcome is the FHIR resource Aidbox uses to report validation
+// and processing errors.
+type OperationOutcome struct {
+	ResourceType string                  `json:"resourceType"`
+	Issue        []OperationOutcomeIssue `json:"issue"`
+}
+
+// OperationOutcomeIssue is a single error or warning.
+type OperationOutcomeIssue struct {
+	Severity    string   `json:"severity"`
+	Code        string   `json:"code"`
+	Diagnostics string   `json:"diagnostics"`
+	Expression  []string `json:"expression"`
+	Details     *struct {
+		Text string `json:"text"`
+	} `json:"details"`
+}
+
+// Message returns the most specific description of the issue.
+func (i OperationOutcomeIssue) Message() string {
+	if i.Diagnostics != "" {
+		return i.Diagnostics
+	}
+	if i.Details != nil && i.Details.Text != "" {
+		return i.Details.Text
+	}
+	return i.Code
+}
+
+// OperationOutcomeFromError returns the OperationOutcome carried by the
+// body of an *APIError, or nil.
+func OperationOutcomeFromError(err error) *OperationOutcome {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+
+	var outcome OperationOutcome
+	if json.Unmarshal([]byte(apiErr.Body), &outcome) != nil || outcome.ResourceType != "OperationOutcome" || len(outcome.Issue) == 0 {
+		return nil
+	}
+	return &outcome
+}
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PollOptions configures Poll.
+type PollOptions struct {
+	// Description names the operation in log messages and errors, e.g.
+	// "FHIR package installation".
+	Description string
+	// Interval is the delay between the first checks. Defaults to 2 seconds.
+	Interval time.Duration
+	// Backoff multiplies the delay after each check. Values below 1 disable
+	// backoff.
+	Backoff float64
+	// MaxInterval caps the delay. Defaults to 1 minute.
+	MaxInterval time.Duration
+	// Timeout bounds the whole operation. Zero only applies the deadline of
+	// the context.
+	Timeout time.Duration
+}
+
+// PollFunc checks a long-running operation. It reports whether the
+// operation is done and, while it is not, an optional progress message.
+// An error stops polling.
+type PollFunc func(ctx context.Context) (done bool, progress string, err error)
+
+// ErrPollTimeout is returned by Poll when the timeout elapses before the
+// operation completes.
+var ErrPollTimeout = errors.New("timed out")
+
+// Poll calls check until the operation is done, check fails, the timeout
+// elapses or ctx is cancelled. Progress is logged at INFO level whenever it
+// changes.
+func Poll(ctx context.Context, opts PollOptions, check PollFunc) error {
+	if opts.Interval <= 0 {
+		opts.Interval = 2 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = time.Minute
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	delay := opts.Interval
+	lastProgress := ""
+	for attempt := 1; ; attempt++ {
+		done, progress, err := check(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return pollContextError(ctx, opts, lastProgress)
+			}
+			return err
+		}
+		if done {
+			tflog.Debug(ctx, opts.Description+" completed", map[string]interface{}{"attempts": attempt, "elapsed": time.Since(start).String()})
+			return nil
+		}
+
+		if progress != lastProgress || attempt == 1 {
+			tflog.Info(ctx, "Waiting for "+opts.Description, map[string]interface{}{"progress": progress, "elapsed": time.Since(start).Round(time.Second).String()})
+			lastProgress = progress
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return pollContextError(ctx, opts, lastProgress)
+		case <-timer.C:
+		}
+
+		if opts.Backoff > 1 {
+			delay = time.Duration(float64(delay) * opts.Backoff)
+		}
+		if delay > opts.MaxInterval {
+			delay = opts.MaxInterval
+		}
+	}
+}
+
+func pollContextError(ctx context.Context, opts PollOptions, progress string) error {
+	last := ""
+	if progress != "" {
+		last = fmt.Sprintf(" (last progress: %s)", progress)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w waiting for %s%s", ErrPollTimeout, opts.Description, last)
+	}
+	return fmt.Errorf("cancelled waiting for %s%s: %w", opts.Description, last, ctx.Err())
+}
+
+// WaitAsync polls the status URL of an asynchronous request until it
+// completes and returns its final status.
+func (c *AidboxHTTPClient) WaitAsync(ctx context.Context, statusURL string, opts PollOptions) (AsyncStatus, error) {
+	var status AsyncStatus
+	err := Poll(ctx, opts, func(ctx context.Context) (bool, string, error) {
+		var err error
+		status, err = c.GetAsyncStatus(ctx, statusURL)
+		return status.Done, status.Progress, err
+	})
+	return status, err
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPoll(t *testing.T) {
+	opts := PollOptions{Description: "test operation", Interval: time.Millisecond, Backoff: 2, MaxInterval: 4 * time.Millisecond}
+
+	t.Run("done", func(t *testing.T) {
+		checks := 0
+		err := Poll(context.Background(), opts, func(ctx context.Context) (bool, string, error) {
+			checks++
+			return checks == 3, "step", nil
+		})
+		if err != nil || checks != 3 {
+			t.Errorf("expected 3 checks and no error, got %d, %v", checks, err)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		failure := errors.New("failed")
+		err := Poll(context.Background(), opts, func(ctx context.Context) (bool, string, error) {
+			return false, "", failure
+		})
+		if !errors.Is(err, failure) {
+			t.Errorf("expected the check error, got %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		opts := opts
+		opts.Timeout = 20 * time.Millisecond
+		err := Poll(context.Background(), opts, func(ctx context.Context) (bool, string, error) {
+			return false, "42%", nil
+		})
+		if !errors.Is(err, ErrPollTimeout) {
+			t.Fatalf("expected a timeout, got %v", err)
+		}
+		if err.Error() != "timed out waiting for test operation (last progress: 42%)" {
+			t.Errorf("unexpected error %q", err)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		checks := 0
+		err := Poll(ctx, opts, func(ctx context.Context) (bool, string, error) {
+			checks++
+			if checks == 2 {
+				cancel()
+			}
+			return false, "", nil
+		})
+		if !errors.Is(err, context.Canceled) || errors.Is(err, ErrPollTimeout) {
+			t.Errorf("expected a cancellation, got %v", err)
+		}
+	})
+}
+
+func TestReindexWaitAsync(t *testing.T) {
+	polls := 0
+	var client *AidboxHTTPClient
+	client = newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Patient/$reindex":
+			if r.Header.Get("Prefer") != "respond-async" {
+				t.Errorf("expected Prefer: respond-async, got %q", r.Header.Get("Prefer"))
+			}
+			w.Header().Set("Content-Location", client.InstanceURL+"/async/reindex")
+			w.WriteHeader(http.StatusAccepted)
+		case "/async/reindex":
+			polls++
+			if polls < 3 {
+				w.Header().Set("X-Progress", "indexing")
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			_, _ = w.Write([]byte(`{"resourceType": "OperationOutcome"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	ctx := context.Background()
+	statusURL, err := client.Reindex(ctx, "Patient")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := client.WaitAsync(ctx, statusURL, PollOptions{Description: "reindex", Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Done || polls != 3 {
+		t.Errorf("expected a completed status after 3 polls, got %+v after %d", status, polls)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PortalProject is a project of the Aidbox portal. Licenses and members
+// belong to a project.
+type PortalProject struct {
+	ID          string `yaml:"id"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Meta        Meta   `yaml:"meta"`
+}
+
+// PortalMember is a user with access to a portal project. Members are
+// invited by email and become active once they accept.
+type PortalMember struct {
+	ID      string  `yaml:"id"`
+	Email   string  `yaml:"email"`
+	Role    string  `yaml:"role"`
+	Status  string  `yaml:"status"`
+	Project Project `yaml:"project"`
+}
+
+// portalCall invokes a portal RPC method with the configured token and
+// decodes its result into out.
+func (c *AidboxHTTPClient) portalCall(ctx context.Context, method string, params map[string]interface{}, out interface{}) error {
+	params["token"] = c.Token
+
+	bodyBytes, _, err := c.makeAPICall(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return unmarshalYAML(bodyBytes, out)
+}
+
+// isNotProjectMember reports whether the portal denied access to a project,
+// which is also how it reports projects that do not exist.
+func isNotProjectMember(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "You are not a member of the project")
+}
+
+// isPortalObjectGone reports whether err of a portal call means that the
+// object does not exist. Since the portal reports missing projects as denied
+// access, denied access only counts when the token is confirmed to be valid,
+// so that a wrong or rotated token does not make every object look deleted.
+// Otherwise the error is returned.
+func (c *AidboxHTTPClient) isPortalObjectGone(ctx context.Context, err error) (bool, error) {
+	if IsNotFound(err) {
+		return true, nil
+	}
+	if !isNotProjectMember(err) {
+		return false, err
+	}
+	if _, whoAmIErr := c.WhoAmI(ctx); whoAmIErr != nil {
+		return false, fmt.Errorf("%w (the portal token could not be verified: %s)", err, whoAmIErr)
+	}
+	return true, nil
+}
+
+func (c *AidboxHTTPClient) CreatePortalProject(ctx context.Context, name, description string) (PortalProject, error) {
+	var resp struct {
+		Result struct {
+			Project PortalProject `yaml:"project"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/create-project", map[string]interface{}{
+		"name":        name,
+		"description": description,
+	}, &resp)
+	return resp.Result.Project, err
+}
+
+// GetPortalProject returns nil when the project does not exist or the token,
+// once confirmed to be valid, has no access to it.
+func (c *AidboxHTTPClient) GetPortalProject(ctx context.Context, id string) (*PortalProject, error) {
+	var resp struct {
+		Result struct {
+			Project *PortalProject `yaml:"project"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/get-project", map[string]interface{}{"id": id}, &resp)
+	if err != nil {
+		gone, err := c.isPortalObjectGone(ctx, err)
+		if gone {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Result.Project, nil
+}
+
+func (c *AidboxHTTPClient) UpdatePortalProject(ctx context.Context, project PortalProject) error {
+	return c.portalCall(ctx, "portal.portal/update-project", map[string]interface{}{
+		"id":          project.ID,
+		"name":        project.Name,
+		"description": project.Description,
+	}, nil)
+}
+
+func (c *AidboxHTTPClient) DeletePortalProject(ctx context.Context, id string) error {
+	err := c.portalCall(ctx, "portal.portal/remove-project", map[string]interface{}{"id": id}, nil)
+	if err != nil {
+		_, err = c.isPortalObjectGone(ctx, err)
+	}
+	return err
+}
+
+// InvitePortalMember invites a user to a project by email.
+func (c *AidboxHTTPClient) InvitePortalMember(ctx context.Context, projectID, email, role string) (PortalMember, error) {
+	var resp struct {
+		Result struct {
+			Member PortalMember `yaml:"member"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/invite-member", map[string]interface{}{
+		"project": projectID,
+		"email":   email,
+		"role":    role,
+	}, &resp)
+	return resp.Result.Member, err
+}
+
+// GetPortalMember returns nil when the member is not part of the project.
+func (c *AidboxHTTPClient) GetPortalMember(ctx context.Context, projectID, id string) (*PortalMember, error) {
+	var resp struct {
+		Result struct {
+			Members []PortalMember `yaml:"members"`
+		} `yaml:"result"`
+	}
+	err := c.portalCall(ctx, "portal.portal/project-members", map[string]interface{}{"project": projectID}, &resp)
+	if err != nil {
+		gone, err := c.isPortalObjectGone(ctx, err)
+		if gone {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, m := range resp.Result.Members {
+		if m.ID == id {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *AidboxHTTPClient) UpdatePortalMemberRole(ctx context.Context, projectID, id, role string) error {
+	return c.portalCall(ctx, "portal.portal/update-member-role", map[string]interface{}{
+		"project": projectID,
+		"id":      id,
+		"role":    role,
+	}, nil)
+}
+
+func (c *AidboxHTTPClient) RemovePortalMember(ctx context.Context, projectID, id string) error {
+	err := c.portalCall(ctx, "portal.portal/remove-member", map[string]interface{}{
+		"project": projectID,
+		"id":      id,
+	}, nil)
+	if err != nil {
+		_, err = c.isPortalObjectGone(ctx, err)
+	}
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGetPortalMember(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `yaml:"method"`
+			Params map[string]interface{} `yaml:"params"`
+		}
+		if err := yaml.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Method == "portal.portal/whoami" {
+			if req.Params["token"] != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte("error:\n  message: Invalid token\n"))
+				return
+			}
+			_, _ = w.Write([]byte("result:\n  user: {id: user-1, email: dev@example.com}\n"))
+			return
+		}
+		if req.Method != "portal.portal/project-members" && req.Method != "portal.portal/remove-member" {
+			t.Errorf("unexpected request %+v", req)
+		}
+
+		if req.Params["project"] != "project-1" || req.Params["token"] != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("error:\n  message: You are not a member of the project\n"))
+			return
+		}
+		_, _ = w.Write([]byte("result:\n  members:\n" +
+			"  - {id: member-1, email: dev@example.com, role: admin, status: active}\n" +
+			"  - {id: member-2, email: ops@example.com, role: member, status: invited}\n"))
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "token", nil)
+	ctx := context.Background()
+
+	member, err := client.GetPortalMember(ctx, "project-1", "member-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member == nil || member.Email != "ops@example.com" || member.Status != "invited" {
+		t.Errorf("unexpected member %+v", member)
+	}
+
+	if member, err := client.GetPortalMember(ctx, "project-1", "member-3"); err != nil || member != nil {
+		t.Errorf("expected no member, got %+v, %v", member, err)
+	}
+
+	// Projects a valid token has no access to are reported as missing.
+	if member, err := client.GetPortalMember(ctx, "project-2", "member-1"); err != nil || member != nil {
+		t.Errorf("expected no member, got %+v, %v", member, err)
+	}
+
+	// A wrong token does not make members look deleted.
+	wrong := NewClient(client.Endpoint, "rotated", nil)
+	if member, err := wrong.GetPortalMember(ctx, "project-1", "member-1"); err == nil || member != nil {
+		t.Errorf("expected an error for a wrong token, got %+v, %v", member, err)
+	}
+	if err := wrong.RemovePortalMember(ctx, "project-1", "member-1"); err == nil {
+		t.Error("expected removing a member with a wrong token to fail")
+	}
+}
+
+func TestCreateHostedInstance(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `yaml:"method"`
+			Params map[string]interface{} `yaml:"params"`
+		}
+		if err := yaml.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Method != "portal.portal/create-hosted-instance" || req.Params["license"] != "license-1" || req.Params["name"] != "pr-42" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		if _, ok := req.Params["region"]; ok {
+			t.Error("expected an empty region not to be sent")
+		}
+		_, _ = w.Write([]byte("result:\n  instance:\n    id: hi-1\n    name: pr-42\n    status: provisioning\n    admin-client-id: root\n    admin-client-secret: s3cret\n"))
+	}))
+	defer srv.Close()
+
+	instance, err := NewClient(srv.URL, "token", nil).CreateHostedInstance(context.Background(), "license-1", "pr-42", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instance.ID != "hi-1" || instance.Status != "provisioning" || instance.AdminClientSecret != "s3cret" {
+		t.Errorf("unexpected instance %+v", instance)
+	}
+}
+
+func TestGetHostedInstanceAccessDenied(t *testing.T) {
+	validToken := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `yaml:"method"`
+		}
+		if err := yaml.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case req.Method == "portal.portal/whoami" && validToken:
+			_, _ = w.Write([]byte("result:\n  user: {id: user-1}\n"))
+		case req.Method == "portal.portal/whoami":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("error:\n  message: Invalid token\n"))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("error:\n  message: You are not a member of the project\n"))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewClient(srv.URL, "token", nil)
+	ctx := context.Background()
+
+	if instance, err := client.GetHostedInstance(ctx, "hi-1"); err != nil || instance != nil {
+		t.Errorf("expected a missing instance, got %+v, %v", instance, err)
+	}
+
+	validToken = false
+	if instance, err := client.GetHostedInstance(ctx, "hi-1"); err == nil || instance != nil {
+		t.Errorf("expected an error for an invalid token, got %+v, %v", instance, err)
+	}
+	if err := client.DeleteHostedInstance(ctx, "hi-1"); err == nil {
+		t.Error("expected deleting with an invalid token to fail")
+	}
+}
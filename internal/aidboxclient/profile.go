@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfileName is used when no profile is explicitly requested.
+const DefaultProfileName = "default"
+
+// ErrProfileNotFound is returned when the requested profile is not defined in
+// the credentials file.
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Profile holds the connection settings of a named credentials profile.
+type Profile struct {
+	Endpoint     string `yaml:"endpoint"`
+	Token        string `yaml:"token"`
+	InstanceURL  string `yaml:"instance_url"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+}
+
+// DefaultCredentialsFile returns the path of ~/.aidbox/credentials.
+func DefaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".aidbox", "credentials"), nil
+}
+
+// LoadProfile reads the named profile from a credentials file. The file may
+// be written either in INI format, with one [section] per profile, or in
+// YAML format, with one top-level key per profile.
+func LoadProfile(path, name string) (Profile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var profiles map[string]Profile
+	if isINI(string(content)) {
+		profiles, err = parseINIProfiles(string(content))
+	} else {
+		profiles, err = parseYAMLProfiles(content)
+	}
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, name, path)
+	}
+	return profile, nil
+}
+
+// isINI reports whether the first meaningful line of content is a section header.
+func isINI(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		return strings.HasPrefix(line, "[")
+	}
+	return false
+}
+
+// parseYAMLProfiles rejects unknown keys, like parseINIProfiles, so that a
+// misspelled key is reported rather than ignored.
+func parseYAMLProfiles(content []byte) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profiles); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func parseINIProfiles(content string) (map[string]Profile, error) {
+	profiles := map[string]Profile{}
+	section := ""
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header", lineNo)
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			if _, ok := profiles[section]; !ok {
+				profiles[section] = Profile{}
+			}
+			continue
+		}
+
+		if section == "" {
+			return nil, fmt.Errorf("line %d: key outside of a profile section", lineNo)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		profile := profiles[section]
+		switch key {
+		case "endpoint":
+			profile.Endpoint = value
+		case "token":
+			profile.Token = value
+		case "instance_url":
+			profile.InstanceURL = value
+		case "client_id":
+			profile.ClientID = value
+		case "client_secret":
+			profile.ClientSecret = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", lineNo, key)
+		}
+		profiles[section] = profile
+	}
+
+	return profiles, scanner.Err()
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadProfile_INI(t *testing.T) {
+	path := writeCredentialsFile(t, `
+# shared developer credentials
+[default]
+token = portal-token
+
+[profile staging]
+instance_url = https://staging.example.com
+client_id    = terraform
+client_secret = "s3cret"
+`)
+
+	profile, err := LoadProfile(path, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if profile.Token != "portal-token" {
+		t.Errorf("expected token %q, got %q", "portal-token", profile.Token)
+	}
+
+	profile, err = LoadProfile(path, "staging")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := Profile{InstanceURL: "https://staging.example.com", ClientID: "terraform", ClientSecret: "s3cret"}
+	if profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, profile)
+	}
+}
+
+func TestLoadProfile_YAML(t *testing.T) {
+	path := writeCredentialsFile(t, `
+default:
+  endpoint: https://portal.example.com/rpc
+  token: portal-token
+ci:
+  instance_url: https://ci.example.com
+  client_id: ci
+  client_secret: ci-secret
+`)
+
+	profile, err := LoadProfile(path, "ci")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := Profile{InstanceURL: "https://ci.example.com", ClientID: "ci", ClientSecret: "ci-secret"}
+	if profile != expected {
+		t.Errorf("expected %+v, got %+v", expected, profile)
+	}
+
+	profile, err = LoadProfile(path, "default")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if profile.Endpoint != "https://portal.example.com/rpc" {
+		t.Errorf("unexpected endpoint %q", profile.Endpoint)
+	}
+}
+
+func TestLoadProfile_Errors(t *testing.T) {
+	if _, err := LoadProfile(filepath.Join(t.TempDir(), "missing"), "default"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	path := writeCredentialsFile(t, "[default]\ntoken = x\n")
+	if _, err := LoadProfile(path, "prod"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	path = writeCredentialsFile(t, "[default]\nregion = eu\n")
+	if _, err := LoadProfile(path, "default"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+
+	path = writeCredentialsFile(t, "default:\n  client_id: ci\n  client_secert: ci-secret\n")
+	if _, err := LoadProfile(path, "default"); err == nil {
+		t.Error("expected an error for an unknown YAML key")
+	}
+
+	path = writeCredentialsFile(t, "")
+	if _, err := LoadProfile(path, "default"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound for an empty file, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// AidboxQuery is a custom SQL endpoint exposed at /$query/<id>.
+type AidboxQuery struct {
+	ResourceType string                      `json:"resourceType"`
+	ID           string                      `json:"id,omitempty"`
+	Query        string                      `json:"query"`
+	CountQuery   string                      `json:"count-query,omitempty"`
+	Params       map[string]AidboxQueryParam `json:"params,omitempty"`
+}
+
+// AidboxQueryParam describes a parameter of an AidboxQuery.
+type AidboxQueryParam struct {
+	Type       string      `json:"type"`
+	Default    interface{} `json:"default,omitempty"`
+	IsRequired *bool       `json:"isRequired,omitempty"`
+	Format     string      `json:"format,omitempty"`
+}
+
+// QueryResult is the response of /$query/<id>.
+type QueryResult struct {
+	Data  []map[string]interface{} `json:"data"`
+	Total *int64                   `json:"total"`
+}
+
+// RunQuery executes the AidboxQuery named name with the given parameters.
+func (c *AidboxHTTPClient) RunQuery(ctx context.Context, name string, params map[string]string) (QueryResult, error) {
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
+
+	p := "/$query/" + url.PathEscape(name)
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
+
+	var result QueryResult
+	err := c.instanceJSON(ctx, "GET", p, nil, &result)
+	return result, err
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// The collection argument of the resource methods below is the resource
+// type, optionally prefixed with "fhir/" to use the FHIR API instead of the
+// Aidbox API, e.g. "SearchParameter" or "fhir/SearchParameter".
+
+func resourcePath(collection, id string) string {
+	return "/" + strings.Trim(collection, "/") + "/" + url.PathEscape(id)
+}
+
+// GetResource reads a resource from the instance into out.
+func (c *AidboxHTTPClient) GetResource(ctx context.Context, collection, id string, out interface{}) error {
+	return c.instanceJSON(ctx, "GET", resourcePath(collection, id), nil, out)
+}
+
+// PutResource creates or replaces a resource on the instance. The stored
+// resource is decoded into out when it is not nil.
+func (c *AidboxHTTPClient) PutResource(ctx context.Context, collection, id string, in, out interface{}) error {
+	return c.instanceJSON(ctx, "PUT", resourcePath(collection, id), in, out)
+}
+
+// DeleteResource deletes a resource from the instance. Deleting a resource
+// that does not exist is not an error.
+func (c *AidboxHTTPClient) DeleteResource(ctx context.Context, collection, id string) error {
+	err := c.instanceJSON(ctx, "DELETE", resourcePath(collection, id), nil, nil)
+	if IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// Reference is a reference to another resource.
+type Reference struct {
+	ID           string `json:"id"`
+	ResourceType string `json:"resourceType"`
+}
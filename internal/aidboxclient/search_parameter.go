@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// SearchParameter is a FHIR SearchParameter, stored through the FHIR API.
+type SearchParameter struct {
+	ResourceType string   `json:"resourceType"`
+	ID           string   `json:"id,omitempty"`
+	URL          string   `json:"url"`
+	Name         string   `json:"name"`
+	Code         string   `json:"code"`
+	Status       string   `json:"status"`
+	Description  string   `json:"description,omitempty"`
+	Base         []string `json:"base"`
+	Type         string   `json:"type"`
+	Expression   string   `json:"expression"`
+}
+
+// AidboxSearchParameter is an Aidbox-native SearchParameter, whose
+// expression is a list of element paths, e.g. [["name", "given"]].
+type AidboxSearchParameter struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id,omitempty"`
+	Name         string          `json:"name"`
+	Description  string          `json:"description,omitempty"`
+	Type         string          `json:"type"`
+	Resource     Reference       `json:"resource"`
+	Expression   [][]interface{} `json:"expression"`
+}
+
+// Reindex rebuilds the search indexes of a resource type. It returns the
+// status URL of the reindex, or "" when it completed synchronously.
+func (c *AidboxHTTPClient) Reindex(ctx context.Context, resourceType string) (string, error) {
+	resp, err := c.doInstanceWithHeader(ctx, "POST", "/"+url.PathEscape(resourceType)+"/$reindex", asyncHeader(""), nil)
+	if err != nil {
+		return "", err
+	}
+	return asyncStatusURL(resp), nil
+}
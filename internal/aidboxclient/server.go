@@ -0,0 +1,194 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ServerVersion describes the Aidbox build reported by /$version.
+type ServerVersion struct {
+	Version string
+	Channel string
+	Commit  string
+}
+
+type versionFields struct {
+	Version string `json:"version"`
+	Channel string `json:"channel"`
+	Commit  string `json:"commit"`
+}
+
+// GetServerVersion calls /$version on the instance. It is also a cheap way
+// to verify the instance credentials.
+func (c *AidboxHTTPClient) GetServerVersion(ctx context.Context) (ServerVersion, error) {
+	var raw struct {
+		versionFields
+		// Some releases nest the build information.
+		Release *versionFields `json:"release"`
+		Aidbox  *versionFields `json:"aidbox"`
+	}
+	if err := c.instanceJSON(ctx, "GET", "/$version", nil, &raw); err != nil {
+		return ServerVersion{}, err
+	}
+
+	fields := raw.versionFields
+	for _, nested := range []*versionFields{raw.Release, raw.Aidbox} {
+		if fields.Version == "" && nested != nil {
+			fields = *nested
+		}
+	}
+
+	return ServerVersion(fields), nil
+}
+
+// ServerInfo describes the connected Aidbox instance: its version and the
+// resource types and operations advertised by /$metadata.
+type ServerInfo struct {
+	Version       ServerVersion
+	ResourceTypes map[string]bool
+	Operations    map[string]bool
+}
+
+// ServerFeature is an instance capability that resources can depend on. A
+// feature is available when the server is at least MinVersion (if set) and
+// advertises ResourceType (if set).
+type ServerFeature struct {
+	Name         string
+	MinVersion   string
+	ResourceType string
+}
+
+var (
+	// FeatureFHIRSchema is FHIR Schema based validation, which replaced
+	// zen-based configuration.
+	FeatureFHIRSchema = ServerFeature{Name: "FHIR Schema", ResourceType: "FHIRSchema"}
+	// FeatureTopicSubscriptions is topic-based subscriptions
+	// (AidboxSubscriptionTopic and AidboxTopicDestination).
+	FeatureTopicSubscriptions = ServerFeature{Name: "topic-based subscriptions", ResourceType: "AidboxSubscriptionTopic"}
+	// FeatureSubsSubscription is the legacy SubsSubscription resource.
+	FeatureSubsSubscription = ServerFeature{Name: "SubsSubscription", ResourceType: "SubsSubscription"}
+	// FeatureMultibox is Multibox, which hosts many boxes on one server.
+	FeatureMultibox = ServerFeature{Name: "Multibox", ResourceType: "Box"}
+)
+
+// ErrFeatureUnsupported is returned by ServerInfo.Require for features the
+// connected instance does not provide.
+var ErrFeatureUnsupported = errors.New("feature not supported by the connected Aidbox instance")
+
+// AtLeast reports whether the server version is at least minVersion.
+// Unparseable versions (e.g. "edge" builds) are assumed to be recent.
+func (i ServerInfo) AtLeast(minVersion string) bool {
+	current, err := version.NewVersion(i.Version.Version)
+	if err != nil {
+		return true
+	}
+	required, err := version.NewVersion(minVersion)
+	if err != nil {
+		return true
+	}
+	return current.GreaterThanOrEqual(required)
+}
+
+// HasResourceType reports whether /$metadata advertised resourceType. When
+// no metadata could be fetched, every resource type is assumed to exist.
+func (i ServerInfo) HasResourceType(resourceType string) bool {
+	if len(i.ResourceTypes) == 0 {
+		return true
+	}
+	return i.ResourceTypes[resourceType]
+}
+
+// Supports reports whether the server provides feature.
+func (i ServerInfo) Supports(feature ServerFeature) bool {
+	return i.Require(feature) == nil
+}
+
+// Require returns an error wrapping ErrFeatureUnsupported that explains why
+// feature is not available, or nil.
+func (i ServerInfo) Require(feature ServerFeature) error {
+	if feature.MinVersion != "" && !i.AtLeast(feature.MinVersion) {
+		return fmt.Errorf("%w: %s requires Aidbox %s or later, the server runs %s",
+			ErrFeatureUnsupported, feature.Name, feature.MinVersion, i.Version.Version)
+	}
+	if feature.ResourceType != "" && !i.HasResourceType(feature.ResourceType) {
+		return fmt.Errorf("%w: %s requires the %s resource type, which the server (version %s) does not provide",
+			ErrFeatureUnsupported, feature.Name, feature.ResourceType, i.Version.Version)
+	}
+	return nil
+}
+
+// GetServerInfo probes /$version and /$metadata. Metadata is optional: when
+// it cannot be read, resource types are left empty.
+func (c *AidboxHTTPClient) GetServerInfo(ctx context.Context) (ServerInfo, error) {
+	serverVersion, err := c.GetServerVersion(ctx)
+	if err != nil {
+		return ServerInfo{}, err
+	}
+
+	info := ServerInfo{
+		Version:       serverVersion,
+		ResourceTypes: map[string]bool{},
+		Operations:    map[string]bool{},
+	}
+
+	var metadata struct {
+		Rest []struct {
+			Resource []struct {
+				Type string `json:"type"`
+			} `json:"resource"`
+			Operation []struct {
+				Name string `json:"name"`
+			} `json:"operation"`
+		} `json:"rest"`
+	}
+	if err := c.instanceJSON(ctx, "GET", "/$metadata", nil, &metadata); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			return info, nil
+		}
+		return ServerInfo{}, err
+	}
+
+	for _, rest := range metadata.Rest {
+		for _, r := range rest.Resource {
+			info.ResourceTypes[r.Type] = true
+		}
+		for _, op := range rest.Operation {
+			info.Operations[op.Name] = true
+		}
+	}
+
+	return info, nil
+}
+
+// PortalUser is the account the portal token belongs to.
+type PortalUser struct {
+	ID    string `yaml:"id"`
+	Email string `yaml:"email"`
+}
+
+// WhoAmI returns the portal account of the configured token.
+func (c *AidboxHTTPClient) WhoAmI(ctx context.Context) (PortalUser, error) {
+	bodyBytes, _, err := c.makeAPICall(ctx, "portal.portal/whoami", map[string]interface{}{
+		"token": c.Token,
+	})
+	if err != nil {
+		return PortalUser{}, err
+	}
+
+	var resp struct {
+		Result struct {
+			User PortalUser `yaml:"user"`
+		} `yaml:"result"`
+	}
+	if err := unmarshalYAML(bodyBytes, &resp); err != nil {
+		return PortalUser{}, err
+	}
+	return resp.Result.User, nil
+}
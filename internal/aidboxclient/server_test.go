@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestInstanceClient(t *testing.T, handler http.HandlerFunc) *AidboxHTTPClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	client := NewClient("", "", nil)
+	client.InstanceURL = srv.URL
+	client.ClientID = "terraform"
+	client.ClientSecret = "secret"
+	return client
+}
+
+func TestGetServerVersion(t *testing.T) {
+	testCases := map[string]string{
+		"flat":   `{"version": "2405.1", "channel": "stable", "commit": "abc"}`,
+		"nested": `{"release": {"version": "2405.1", "channel": "stable", "commit": "abc"}}`,
+	}
+
+	for name, body := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if user, pass, ok := r.BasicAuth(); !ok || user != "terraform" || pass != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if r.URL.Path != "/$version" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(body))
+			})
+
+			version, err := client.GetServerVersion(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			expected := ServerVersion{Version: "2405.1", Channel: "stable", Commit: "abc"}
+			if version != expected {
+				t.Errorf("expected %+v, got %+v", expected, version)
+			}
+		})
+	}
+}
+
+func TestGetServerVersion_Unauthorized(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := client.GetServerVersion(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 *APIError, got %T: %v", err, err)
+	}
+}
+
+func TestGetServerVersion_NotConfigured(t *testing.T) {
+	_, err := NewClient("", "", nil).GetServerVersion(context.Background())
+	if !errors.Is(err, ErrInstanceNotConfigured) {
+		t.Fatalf("expected ErrInstanceNotConfigured, got %v", err)
+	}
+}
+
+func TestWhoAmI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("result:\n  user:\n    id: user-1\n    email: dev@example.com\n"))
+	}))
+	defer srv.Close()
+
+	user, err := NewClient(srv.URL, "token", nil).WhoAmI(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if user.ID != "user-1" || user.Email != "dev@example.com" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestGetServerInfo(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/$version":
+			_, _ = w.Write([]byte(`{"version": "2405.1"}`))
+		case "/$metadata":
+			_, _ = w.Write([]byte(`{"rest": [{"resource": [{"type": "Patient"}, {"type": "FHIRSchema"}], "operation": [{"name": "export"}]}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	info, err := client.GetServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !info.Supports(FeatureFHIRSchema) {
+		t.Error("expected FHIR Schema to be supported")
+	}
+	if info.Supports(FeatureTopicSubscriptions) {
+		t.Error("expected topic-based subscriptions not to be supported")
+	}
+	if !info.Operations["export"] {
+		t.Error("expected the export operation to be detected")
+	}
+}
+
+func TestGetServerInfo_WithoutMetadata(t *testing.T) {
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/$version" {
+			_, _ = w.Write([]byte(`{"version": "2405.1"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	info, err := client.GetServerInfo(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !info.HasResourceType("AidboxSubscriptionTopic") {
+		t.Error("expected resource types to be assumed present without metadata")
+	}
+}
+
+func TestServerInfo_AtLeast(t *testing.T) {
+	testCases := []struct {
+		version  string
+		min      string
+		expected bool
+	}{
+		{"2405.1", "2402", true},
+		{"2402.0", "2402", true},
+		{"2309.3", "2402", false},
+		{"edge", "2402", true},
+	}
+
+	for _, tc := range testCases {
+		info := ServerInfo{Version: ServerVersion{Version: tc.version}}
+		if got := info.AtLeast(tc.min); got != tc.expected {
+			t.Errorf("AtLeast(%q) on %q: expected %t, got %t", tc.min, tc.version, tc.expected, got)
+		}
+	}
+}
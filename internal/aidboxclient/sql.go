@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExecuteSQL runs a statement through the /$sql endpoint of the instance.
+// Parameters are bound to the "?" placeholders of the query. Rows are
+// returned for queries; other statements return an empty result.
+func (c *AidboxHTTPClient) ExecuteSQL(ctx context.Context, query string, params ...interface{}) ([]map[string]interface{}, error) {
+	body := append([]interface{}{query}, params...)
+
+	var raw json.RawMessage
+	if err := c.instanceJSON(ctx, "POST", "/$sql", body, &raw); err != nil {
+		return nil, err
+	}
+
+	// DDL statements return a summary object instead of rows.
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return nil, nil
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, fmt.Errorf("failed to parse SQL result: %w", err)
+	}
+	return rows, nil
+}
+
+// QuoteIdentifier quotes a Postgres identifier such as a table or index name.
+func QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// RPCError is the error member of an instance RPC response.
+type RPCError struct {
+	Method  string
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("RPC %s failed: %s", e.Method, e.Message)
+}
+
+// CallRPC invokes an RPC method of the instance (POST /rpc) and decodes the
+// result into out.
+func (c *AidboxHTTPClient) CallRPC(ctx context.Context, method string, params, out interface{}) error {
+	var resp struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+
+	err := c.instanceJSON(ctx, "POST", "/rpc", map[string]interface{}{
+		"method": method,
+		"params": params,
+	}, &resp)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Error) > 0 && string(resp.Error) != "null" {
+		var message struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(resp.Error, &message) != nil || message.Message == "" {
+			message.Message = Redact(string(resp.Error))
+		}
+		return &RPCError{Method: method, Message: message.Message}
+	}
+
+	if out == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, out); err != nil {
+		return fmt.Errorf("failed to parse %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// sqlMigrationTable records the migrations applied by the provider.
+const sqlMigrationTable = "public.terraform_sql_migration"
+
+// SQLMigration is a migration recorded as applied.
+type SQLMigration struct {
+	ID        string
+	Checksum  string
+	AppliedAt string
+}
+
+// SQLChecksum returns the checksum recorded for a migration script.
+func SQLChecksum(sql string) string {
+	sum := sha256.Sum256([]byte(sql))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *AidboxHTTPClient) ensureSQLMigrationTable(ctx context.Context) error {
+	_, err := c.ExecuteSQL(ctx, "CREATE TABLE IF NOT EXISTS "+sqlMigrationTable+
+		" (id text PRIMARY KEY, checksum text NOT NULL, applied_at timestamptz NOT NULL DEFAULT now())")
+	return err
+}
+
+// ApplySQLMigration runs the up script and records the migration. The script
+// and the bookkeeping are separate statements: if recording fails, the
+// script has still been applied.
+func (c *AidboxHTTPClient) ApplySQLMigration(ctx context.Context, id, up string) (SQLMigration, error) {
+	if err := c.ensureSQLMigrationTable(ctx); err != nil {
+		return SQLMigration{}, fmt.Errorf("failed to create migration table: %w", err)
+	}
+
+	if _, err := c.ExecuteSQL(ctx, up); err != nil {
+		return SQLMigration{}, fmt.Errorf("failed to apply migration %s: %w", id, err)
+	}
+
+	_, err := c.ExecuteSQL(ctx, "INSERT INTO "+sqlMigrationTable+" (id, checksum) VALUES (?, ?) "+
+		"ON CONFLICT (id) DO UPDATE SET checksum = EXCLUDED.checksum, applied_at = now()", id, SQLChecksum(up))
+	if err != nil {
+		return SQLMigration{}, fmt.Errorf("migration %s was applied but could not be recorded: %w", id, err)
+	}
+
+	migration, err := c.GetSQLMigration(ctx, id)
+	if err != nil {
+		return SQLMigration{}, err
+	}
+	if migration == nil {
+		return SQLMigration{}, fmt.Errorf("migration %s was applied but its record was not found", id)
+	}
+	return *migration, nil
+}
+
+// GetSQLMigration returns the record of an applied migration, or nil when the
+// migration has not been applied.
+func (c *AidboxHTTPClient) GetSQLMigration(ctx context.Context, id string) (*SQLMigration, error) {
+	rows, err := c.ExecuteSQL(ctx, "SELECT to_regclass(?) IS NOT NULL AS exists", sqlMigrationTable)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 || rows[0]["exists"] != true {
+		return nil, nil
+	}
+
+	rows, err = c.ExecuteSQL(ctx, "SELECT checksum, applied_at::text AS applied_at FROM "+sqlMigrationTable+" WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	checksum, _ := rows[0]["checksum"].(string)
+	appliedAt, _ := rows[0]["applied_at"].(string)
+	return &SQLMigration{ID: id, Checksum: checksum, AppliedAt: appliedAt}, nil
+}
+
+// RevertSQLMigration runs the down script, if any, and forgets the migration.
+func (c *AidboxHTTPClient) RevertSQLMigration(ctx context.Context, id, down string) error {
+	if down != "" {
+		if _, err := c.ExecuteSQL(ctx, down); err != nil {
+			return fmt.Errorf("failed to revert migration %s: %w", id, err)
+		}
+	}
+
+	if err := c.ensureSQLMigrationTable(ctx); err != nil {
+		return err
+	}
+	_, err := c.ExecuteSQL(ctx, "DELETE FROM "+sqlMigrationTable+" WHERE id = ?", id)
+	return err
+}
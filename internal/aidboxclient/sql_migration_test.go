@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeSQLMigrationDB serves /$sql with an in-memory migration table and
+// records the scripts it runs.
+type fakeSQLMigrationDB struct {
+	tableExists bool
+	records     map[string]string
+	scripts     []string
+	failScript  string
+}
+
+func (db *fakeSQLMigrationDB) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/$sql" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		var body []interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		query := body[0].(string)
+
+		switch {
+		case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS "+sqlMigrationTable):
+			db.tableExists = true
+			_, _ = w.Write([]byte(`{"message": "CREATE TABLE"}`))
+		case strings.HasPrefix(query, "SELECT to_regclass"):
+			if body[1] != sqlMigrationTable {
+				t.Errorf("unexpected table %v", body[1])
+			}
+			_, _ = fmt.Fprintf(w, `[{"exists": %t}]`, db.tableExists)
+		case strings.HasPrefix(query, "INSERT INTO "+sqlMigrationTable):
+			db.records[body[1].(string)] = body[2].(string)
+			_, _ = w.Write([]byte(`{"message": "INSERT 0 1"}`))
+		case strings.HasPrefix(query, "SELECT checksum"):
+			if !db.tableExists {
+				t.Errorf("migration table queried before it exists")
+			}
+			checksum, ok := db.records[body[1].(string)]
+			if !ok {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `[{"checksum": %q, "applied_at": "2024-05-01 10:00:00+00"}]`, checksum)
+		case strings.HasPrefix(query, "DELETE FROM "+sqlMigrationTable):
+			delete(db.records, body[1].(string))
+			_, _ = w.Write([]byte(`{"message": "DELETE 1"}`))
+		default:
+			if query == db.failScript {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				_, _ = w.Write([]byte(`{"message": "syntax error"}`))
+				return
+			}
+			db.scripts = append(db.scripts, query)
+			_, _ = w.Write([]byte(`{"message": "OK"}`))
+		}
+	}
+}
+
+func TestSQLChecksum(t *testing.T) {
+	if SQLChecksum("SELECT 1") == SQLChecksum("SELECT 2") {
+		t.Error("expected different scripts to have different checksums")
+	}
+	if got := SQLChecksum(""); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("unexpected checksum %s", got)
+	}
+}
+
+func TestApplySQLMigration(t *testing.T) {
+	db := &fakeSQLMigrationDB{records: map[string]string{}}
+	client := newTestInstanceClient(t, db.handler(t))
+
+	up := "CREATE VIEW active_patient AS SELECT * FROM patient"
+	migration, err := client.ApplySQLMigration(context.Background(), "0001", up)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.scripts) != 1 || db.scripts[0] != up {
+		t.Errorf("expected the up script to run once, got %q", db.scripts)
+	}
+	if db.records["0001"] != SQLChecksum(up) {
+		t.Errorf("expected checksum %s to be recorded, got %q", SQLChecksum(up), db.records["0001"])
+	}
+	if migration.ID != "0001" || migration.Checksum != SQLChecksum(up) || migration.AppliedAt == "" {
+		t.Errorf("unexpected migration %+v", migration)
+	}
+}
+
+func TestApplySQLMigrationFailure(t *testing.T) {
+	up := "CREATE VIEW broken AS SELEC"
+	db := &fakeSQLMigrationDB{records: map[string]string{}, failScript: up}
+	client := newTestInstanceClient(t, db.handler(t))
+
+	if _, err := client.ApplySQLMigration(context.Background(), "0001", up); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(db.records) != 0 {
+		t.Errorf("expected a failed migration not to be recorded, got %v", db.records)
+	}
+}
+
+func TestGetSQLMigration(t *testing.T) {
+	db := &fakeSQLMigrationDB{records: map[string]string{}}
+	client := newTestInstanceClient(t, db.handler(t))
+
+	migration, err := client.GetSQLMigration(context.Background(), "0001")
+	if err != nil || migration != nil {
+		t.Errorf("expected no migration without the table, got %+v, %v", migration, err)
+	}
+
+	db.tableExists = true
+	migration, err = client.GetSQLMigration(context.Background(), "0001")
+	if err != nil || migration != nil {
+		t.Errorf("expected no migration without a record, got %+v, %v", migration, err)
+	}
+
+	db.records["0001"] = "abc"
+	migration, err = client.GetSQLMigration(context.Background(), "0001")
+	if err != nil || migration == nil || migration.Checksum != "abc" {
+		t.Errorf("expected the recorded migration, got %+v, %v", migration, err)
+	}
+}
+
+func TestRevertSQLMigration(t *testing.T) {
+	testCases := map[string]struct {
+		down    string
+		scripts int
+	}{
+		"with down":    {down: "DROP VIEW active_patient", scripts: 1},
+		"without down": {down: "", scripts: 0},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := &fakeSQLMigrationDB{tableExists: true, records: map[string]string{"0001": "abc", "0002": "def"}}
+			client := newTestInstanceClient(t, db.handler(t))
+
+			if err := client.RevertSQLMigration(context.Background(), "0001", tc.down); err != nil {
+				t.Fatal(err)
+			}
+			if len(db.scripts) != tc.scripts || (tc.scripts > 0 && db.scripts[0] != tc.down) {
+				t.Errorf("expected %d down scripts, got %q", tc.scripts, db.scripts)
+			}
+			if _, ok := db.records["0001"]; ok {
+				t.Error("expected the migration record to be deleted")
+			}
+			if _, ok := db.records["0002"]; !ok {
+				t.Error("expected other migration records to be kept")
+			}
+		})
+	}
+}
+
+func TestRevertSQLMigrationFailure(t *testing.T) {
+	down := "DROP VIEW missing"
+	db := &fakeSQLMigrationDB{tableExists: true, records: map[string]string{"0001": "abc"}, failScript: down}
+	client := newTestInstanceClient(t, db.handler(t))
+
+	if err := client.RevertSQLMigration(context.Background(), "0001", down); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := db.records["0001"]; !ok {
+		t.Error("expected the record to be kept when the down script fails")
+	}
+}
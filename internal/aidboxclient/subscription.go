@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import "encoding/json"
+
+// SubscriptionTopic is an AidboxSubscriptionTopic, stored through the FHIR
+// API.
+type SubscriptionTopic struct {
+	ResourceType string                     `json:"resourceType"`
+	ID           string                     `json:"id,omitempty"`
+	URL          string                     `json:"url"`
+	Status       string                     `json:"status"`
+	Description  string                     `json:"description,omitempty"`
+	Trigger      []SubscriptionTopicTrigger `json:"trigger"`
+}
+
+// SubscriptionTopicTrigger selects the resource changes published to a
+// topic.
+type SubscriptionTopicTrigger struct {
+	Resource             string   `json:"resource"`
+	Description          string   `json:"description,omitempty"`
+	SupportedInteraction []string `json:"supportedInteraction,omitempty"`
+	FHIRPathCriteria     string   `json:"fhirPathCriteria,omitempty"`
+}
+
+// TopicDestination is an AidboxTopicDestination, stored through the FHIR
+// API. The delivery settings are kind-specific parameters.
+type TopicDestination struct {
+	ResourceType string                      `json:"resourceType"`
+	ID           string                      `json:"id,omitempty"`
+	Meta         *ResourceMeta               `json:"meta,omitempty"`
+	Kind         string                      `json:"kind"`
+	Topic        string                      `json:"topic"`
+	Parameter    []TopicDestinationParameter `json:"parameter"`
+}
+
+// TopicDestinationParameter is a named parameter of a topic destination.
+// Exactly one of the value fields is set.
+type TopicDestinationParameter struct {
+	Name             string `json:"name"`
+	ValueString      string `json:"valueString,omitempty"`
+	ValueURL         string `json:"valueUrl,omitempty"`
+	ValueUnsignedInt *int64 `json:"valueUnsignedInt,omitempty"`
+	ValueInteger     *int64 `json:"valueInteger,omitempty"`
+}
+
+// ResourceMeta is the metadata of an instance resource.
+type ResourceMeta struct {
+	Profile []string `json:"profile,omitempty"`
+}
+
+// TopicDestinationProfile returns the profile that validates destinations of
+// the given kind.
+func TopicDestinationProfile(kind string) string {
+	return "http://aidbox.app/StructureDefinition/aidboxtopicdestination-" + kind
+}
+
+// SubsSubscription is a legacy Aidbox subscription delivering resource
+// changes to a REST hook.
+type SubsSubscription struct {
+	ResourceType string                             `json:"resourceType"`
+	ID           string                             `json:"id,omitempty"`
+	Status       string                             `json:"status"`
+	Trigger      map[string]SubsSubscriptionTrigger `json:"trigger"`
+	Channel      SubsSubscriptionChannel            `json:"channel"`
+}
+
+// SubsSubscriptionTrigger selects the events of a resource type.
+type SubsSubscriptionTrigger struct {
+	Event  []string        `json:"event,omitempty"`
+	Filter json.RawMessage `json:"filter,omitempty"`
+}
+
+// SubsSubscriptionChannel is the REST hook of a SubsSubscription.
+type SubsSubscriptionChannel struct {
+	Type     string                   `json:"type"`
+	Endpoint string                   `json:"endpoint"`
+	Payload  *SubsSubscriptionPayload `json:"payload,omitempty"`
+	Headers  map[string]string        `json:"headers,omitempty"`
+	Timeout  *int64                   `json:"timeout,omitempty"`
+}
+
+// SubsSubscriptionPayload configures what is sent to the REST hook.
+type SubsSubscriptionPayload struct {
+	Content     string `json:"content,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CodeSystem is a FHIR CodeSystem. Concepts of large code systems are
+// stored as separate Concept resources instead of inline.
+type CodeSystem struct {
+	ResourceType string              `json:"resourceType"`
+	ID           string              `json:"id,omitempty"`
+	URL          string              `json:"url"`
+	Name         string              `json:"name,omitempty"`
+	Version      string              `json:"version,omitempty"`
+	Status       string              `json:"status"`
+	Content      string              `json:"content"`
+	Concept      []CodeSystemConcept `json:"concept,omitempty"`
+}
+
+// CodeSystemConcept is a concept defined inline by a CodeSystem.
+type CodeSystemConcept struct {
+	Code       string `json:"code"`
+	Display    string `json:"display,omitempty"`
+	Definition string `json:"definition,omitempty"`
+}
+
+// Concept is an Aidbox Concept resource, a single code of a code system.
+type Concept struct {
+	ResourceType string `json:"resourceType"`
+	ID           string `json:"id"`
+	System       string `json:"system"`
+	Code         string `json:"code"`
+	Display      string `json:"display,omitempty"`
+	Definition   string `json:"definition,omitempty"`
+}
+
+// ValueSet is a FHIR ValueSet.
+type ValueSet struct {
+	ResourceType string           `json:"resourceType"`
+	ID           string           `json:"id,omitempty"`
+	URL          string           `json:"url"`
+	Name         string           `json:"name,omitempty"`
+	Version      string           `json:"version,omitempty"`
+	Status       string           `json:"status"`
+	Compose      *ValueSetCompose `json:"compose,omitempty"`
+}
+
+// ValueSetCompose selects the codes of a value set.
+type ValueSetCompose struct {
+	Include []ValueSetInclude `json:"include"`
+	Exclude []ValueSetInclude `json:"exclude,omitempty"`
+}
+
+// ValueSetInclude selects codes from a code system or other value sets.
+type ValueSetInclude struct {
+	System   string            `json:"system,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Concept  []ValueSetConcept `json:"concept,omitempty"`
+	Filter   []ValueSetFilter  `json:"filter,omitempty"`
+	ValueSet []string          `json:"valueSet,omitempty"`
+}
+
+// ValueSetConcept is a code listed in a value set.
+type ValueSetConcept struct {
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+// ValueSetFilter selects codes by property.
+type ValueSetFilter struct {
+	Property string `json:"property"`
+	Op       string `json:"op"`
+	Value    string `json:"value"`
+}
+
+// ConceptMap is a FHIR ConceptMap.
+type ConceptMap struct {
+	ResourceType    string            `json:"resourceType"`
+	ID              string            `json:"id,omitempty"`
+	URL             string            `json:"url"`
+	Name            string            `json:"name,omitempty"`
+	Status          string            `json:"status"`
+	SourceCanonical string            `json:"sourceCanonical,omitempty"`
+	TargetCanonical string            `json:"targetCanonical,omitempty"`
+	Group           []ConceptMapGroup `json:"group,omitempty"`
+}
+
+// ConceptMapGroup maps codes from a source to a target code system.
+type ConceptMapGroup struct {
+	Source  string              `json:"source"`
+	Target  string              `json:"target"`
+	Element []ConceptMapElement `json:"element"`
+}
+
+// ConceptMapElement maps a source code.
+type ConceptMapElement struct {
+	Code    string             `json:"code"`
+	Display string             `json:"display,omitempty"`
+	Target  []ConceptMapTarget `json:"target,omitempty"`
+}
+
+// ConceptMapTarget is a target code of a mapping.
+type ConceptMapTarget struct {
+	Code        string `json:"code"`
+	Display     string `json:"display,omitempty"`
+	Equivalence string `json:"equivalence"`
+}
+
+// ValueSetExpansion is the result of ValueSet/$expand.
+type ValueSetExpansion struct {
+	Total    *int64                   `json:"total"`
+	Contains []ValueSetExpansionEntry `json:"contains"`
+}
+
+// ValueSetExpansionEntry is a code of an expanded value set.
+type ValueSetExpansionEntry struct {
+	System  string `json:"system"`
+	Version string `json:"version,omitempty"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+// ExpandValueSet expands the value set with the given canonical URL. An
+// empty filter and a zero count are not sent.
+func (c *AidboxHTTPClient) ExpandValueSet(ctx context.Context, valueSetURL, filter string, count int64) (ValueSetExpansion, error) {
+	query := url.Values{"url": []string{valueSetURL}}
+	if filter != "" {
+		query.Set("filter", filter)
+	}
+	if count > 0 {
+		query.Set("count", strconv.FormatInt(count, 10))
+	}
+
+	var vs struct {
+		Expansion ValueSetExpansion `json:"expansion"`
+	}
+	err := c.instanceJSON(ctx, "GET", "/fhir/ValueSet/$expand?"+query.Encode(), nil, &vs)
+	return vs.Expansion, err
+}
+
+var conceptIDRegexp = regexp.MustCompile(`^[A-Za-z0-9.-]+$`)
+
+// conceptIDPrefix returns the prefix shared by the concept IDs of a code
+// system: the code system ID when it fits, or a hash of it otherwise.
+func conceptIDPrefix(codeSystemID string) string {
+	if len(codeSystemID) <= 31 && conceptIDRegexp.MatchString(codeSystemID) {
+		return codeSystemID
+	}
+	sum := sha256.Sum256([]byte(codeSystemID))
+	return "cs" + hex.EncodeToString(sum[:])[:16]
+}
+
+// ConceptID returns a stable resource ID for a code of a code system. The
+// code is hashed together with the code system, since any separator that is
+// valid in an ID may also appear in a code. The ID starts with a prefix of
+// the code system, so that the concepts of two code systems with the same
+// URL can be told apart.
+func ConceptID(codeSystemID, code string) string {
+	sum := sha256.Sum256([]byte(codeSystemID + "\x00" + code))
+	return conceptIDPrefix(codeSystemID) + "-" + hex.EncodeToString(sum[:])[:32]
+}
+
+// conceptIDPattern returns a Postgres regular expression that matches the
+// IDs ConceptID returns for the code system, and no others.
+func conceptIDPattern(codeSystemID string) string {
+	return "^" + regexp.QuoteMeta(conceptIDPrefix(codeSystemID)) + "-[0-9a-f]{32}$"
+}
+
+// bundleEntry is an entry of a batch Bundle.
+type bundleEntry struct {
+	Resource interface{}   `json:"resource,omitempty"`
+	Request  bundleRequest `json:"request"`
+}
+
+type bundleRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// UploadConcepts creates or replaces concepts with batch requests of at most
+// chunkSize concepts.
+func (c *AidboxHTTPClient) UploadConcepts(ctx context.Context, concepts []Concept, chunkSize int) error {
+	if chunkSize <= 0 {
+		chunkSize = len(concepts)
+	}
+
+	for start := 0; start < len(concepts); start += chunkSize {
+		end := start + chunkSize
+		if end > len(concepts) {
+			end = len(concepts)
+		}
+
+		entries := make([]bundleEntry, 0, end-start)
+		for _, concept := range concepts[start:end] {
+			entries = append(entries, bundleEntry{
+				Resource: concept,
+				Request:  bundleRequest{Method: "PUT", URL: "/Concept/" + url.PathEscape(concept.ID)},
+			})
+		}
+		if err := c.batch(ctx, entries); err != nil {
+			return fmt.Errorf("failed to upload concepts %d to %d: %w", start+1, end, err)
+		}
+	}
+	return nil
+}
+
+// batch submits a batch Bundle and reports the entries that failed.
+func (c *AidboxHTTPClient) batch(ctx context.Context, entries []bundleEntry) error {
+	statuses, err := c.batchStatuses(ctx, entries)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for i, status := range statuses {
+		if !strings.HasPrefix(status, "2") {
+			failed = append(failed, fmt.Sprintf("%s %s: %s", entries[i].Request.Method, entries[i].Request.URL, status))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d batch entries failed: %s", len(failed), len(entries), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// batchStatuses submits a batch Bundle and returns the response status of
+// each entry, e.g. "201 Created".
+func (c *AidboxHTTPClient) batchStatuses(ctx context.Context, entries []bundleEntry) ([]string, error) {
+	var result struct {
+		Entry []struct {
+			Response struct {
+				Status string `json:"status"`
+			} `json:"response"`
+		} `json:"entry"`
+	}
+	err := c.instanceJSON(ctx, "POST", "/", map[string]interface{}{
+		"resourceType": "Bundle",
+		"type":         "batch",
+		"entry":        entries,
+	}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]string, 0, len(entries))
+	for i := range entries {
+		status := "missing response"
+		if i < len(result.Entry) {
+			status = result.Entry[i].Response.Status
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// CountConcepts returns the number of Concept resources of a code system
+// that are stored under the given system URL.
+func (c *AidboxHTTPClient) CountConcepts(ctx context.Context, codeSystemID, system string) (int64, error) {
+	rows, err := c.ExecuteSQL(ctx, "SELECT count(*) AS count FROM concept WHERE id ~ ? AND resource->>'system' = ?", conceptIDPattern(codeSystemID), system)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	count, _ := rows[0]["count"].(float64)
+	return int64(count), nil
+}
+
+// DeleteConcepts deletes the Concept resources of a code system, whatever
+// system URL they are stored under. Concepts of other code systems with the
+// same URL are kept.
+func (c *AidboxHTTPClient) DeleteConcepts(ctx context.Context, codeSystemID string) error {
+	_, err := c.ExecuteSQL(ctx, "DELETE FROM concept WHERE id ~ ?", conceptIDPattern(codeSystemID))
+	return err
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConceptID(t *testing.T) {
+	id := ConceptID("loinc", "8480-6/x")
+	if !strings.HasPrefix(id, "loinc-") || len(id) != len("loinc-")+32 {
+		t.Errorf("expected a prefixed hashed ID, got %q", id)
+	}
+	if id != ConceptID("loinc", "8480-6/x") {
+		t.Error("expected a stable ID")
+	}
+	if id == ConceptID("loinc", "8480-6/y") {
+		t.Error("expected distinct IDs for distinct codes")
+	}
+
+	long := ConceptID(strings.Repeat("a", 40), "A00.1")
+	if len(long) > 64 || !strings.HasPrefix(long, "cs") || !conceptIDRegexp.MatchString(long) {
+		t.Errorf("expected a hashed prefix for long code system IDs, got %q", long)
+	}
+}
+
+func TestConceptIDPattern(t *testing.T) {
+	testCases := map[string]struct {
+		codeSystemID string
+		matches      []string
+		others       []string
+	}{
+		"short": {
+			codeSystemID: "a",
+			matches:      []string{ConceptID("a", "x"), ConceptID("a", "b-c")},
+			others:       []string{ConceptID("a-b", "c"), ConceptID("ab", "x"), "a-x"},
+		},
+		"dotted": {
+			codeSystemID: "icd.10",
+			matches:      []string{ConceptID("icd.10", "A00.1")},
+			others:       []string{ConceptID("icdx10", "A00.1")},
+		},
+		"long": {
+			codeSystemID: strings.Repeat("x", 40),
+			matches:      []string{ConceptID(strings.Repeat("x", 40), "code-1")},
+			others:       []string{ConceptID(strings.Repeat("x", 41), "code-1")},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			pattern := regexp.MustCompile(conceptIDPattern(tc.codeSystemID))
+			for _, id := range tc.matches {
+				if !pattern.MatchString(id) {
+					t.Errorf("expected %q to match %s", id, pattern)
+				}
+			}
+			for _, id := range tc.others {
+				if pattern.MatchString(id) {
+					t.Errorf("expected %q not to match %s", id, pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestCountAndDeleteConcepts(t *testing.T) {
+	var queries [][]interface{}
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body []interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		queries = append(queries, body)
+		if strings.HasPrefix(body[0].(string), "SELECT") {
+			_, _ = w.Write([]byte(`[{"count": 3}]`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"message": "DELETE 3"}`))
+	})
+
+	count, err := client.CountConcepts(context.Background(), "loinc", "http://loinc.org")
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 concepts, got %d, %v", count, err)
+	}
+	if err := client.DeleteConcepts(context.Background(), "loinc"); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern := conceptIDPattern("loinc")
+	if len(queries) != 2 || queries[0][1] != pattern || queries[0][2] != "http://loinc.org" || queries[1][1] != pattern || len(queries[1]) != 2 {
+		t.Errorf("expected queries scoped to the code system, got %v", queries)
+	}
+}
+
+func TestConceptIDCollisions(t *testing.T) {
+	testCases := [][2][2]string{
+		{{"a", "b-c"}, {"a-b", "c"}},
+		{{"icd-10", "A00.1"}, {"icd", "10-A00.1"}},
+		{{strings.Repeat("x", 60), "code-1"}, {strings.Repeat("x", 61), "code-1"}},
+	}
+	for _, tc := range testCases {
+		a, b := ConceptID(tc[0][0], tc[0][1]), ConceptID(tc[1][0], tc[1][1])
+		if a == b {
+			t.Errorf("%q and %q have the same ID %q", tc[0], tc[1], a)
+		}
+	}
+}
+
+func TestUploadConceptsChunks(t *testing.T) {
+	var sizes []int
+	client := newTestInstanceClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var bundle struct {
+			Entry []bundleEntry `json:"entry"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&bundle); err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(bundle.Entry))
+
+		entries := make([]string, 0, len(bundle.Entry))
+		for _, e := range bundle.Entry {
+			status := "200"
+			if strings.HasSuffix(e.Request.URL, "-bad") {
+				status = "422"
+			}
+			entries = append(entries, fmt.Sprintf(`{"response": {"status": %q}}`, status))
+		}
+		_, _ = fmt.Fprintf(w, `{"resourceType": "Bundle", "entry": [%s]}`, strings.Join(entries, ","))
+	})
+
+	concepts := make([]Concept, 5)
+	for i := range concepts {
+		concepts[i] = Concept{ResourceType: "Concept", ID: fmt.Sprintf("cs-%d", i), System: "http://example.org/cs", Code: fmt.Sprint(i)}
+	}
+
+	if err := client.UploadConcepts(context.Background(), concepts, 2); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sizes) != "[2 2 1]" {
+		t.Errorf("unexpected batch sizes %v", sizes)
+	}
+
+	concepts[4].ID = "cs-bad"
+	err := client.UploadConcepts(context.Background(), concepts, 2)
+	if err == nil || !strings.Contains(err.Error(), "concepts 5 to 5") || !strings.Contains(err.Error(), "PUT /Concept/cs-bad: 422") {
+		t.Errorf("expected the failed entry to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportConfig describes how the HTTP transport used to reach Aidbox
+// endpoints is built.
+type TransportConfig struct {
+	// CACertPEM is a PEM-encoded bundle of additional trusted CA certificates.
+	CACertPEM string
+	// CACertFile is a path to a PEM-encoded bundle of additional trusted CA certificates.
+	CACertFile string
+	// ClientCertPEM and ClientKeyPEM are a PEM-encoded certificate and private
+	// key presented to the server for mutual TLS.
+	ClientCertPEM string
+	ClientKeyPEM  string
+	// InsecureSkipVerify disables server certificate verification.
+	InsecureSkipVerify bool
+	// ProxyURL overrides the HTTP(S)_PROXY environment variables when set.
+	ProxyURL string
+}
+
+// NewHTTPClient builds an *http.Client with a dedicated, logging transport
+// configured according to cfg. The system certificate pool is always trusted;
+// the configured CA bundles are added on top of it.
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only set when explicitly requested by the practitioner.
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec
+	}
+
+	if cfg.CACertPEM != "" || cfg.CACertFile != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if cfg.CACertPEM != "" && !pool.AppendCertsFromPEM([]byte(cfg.CACertPEM)) {
+			return nil, errors.New("failed to parse CA certificate PEM: no valid certificates found")
+		}
+
+		if cfg.CACertFile != "" {
+			caBytes, err := os.ReadFile(cfg.CACertFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA certificate file: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(caBytes) {
+				return nil, fmt.Errorf("failed to parse CA certificate file %s: no valid certificates found", cfg.CACertFile)
+			}
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertPEM != "" || cfg.ClientKeyPEM != "" {
+		if cfg.ClientCertPEM == "" || cfg.ClientKeyPEM == "" {
+			return nil, errors.New("both client certificate and client key must be provided for mutual TLS")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", cfg.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	// Mirrors the settings of http.DefaultTransport.
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       tlsConfig,
+	}
+
+	return &http.Client{Transport: NewLoggingTransport(transport)}, nil
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package aidboxclient
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHTTPClient_CustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+
+	untrusted, err := NewHTTPClient(TransportConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := untrusted.Get(srv.URL); err == nil {
+		t.Fatal("expected certificate verification error without custom CA")
+	}
+
+	trusted, err := NewHTTPClient(TransportConfig{CACertPEM: caPEM})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err := trusted.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected request to succeed with custom CA: %s", err)
+	}
+	resp.Body.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte(caPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := NewHTTPClient(TransportConfig{CACertFile: caFile})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err = fromFile.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected request to succeed with CA file: %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClient_InsecureSkipVerify(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client, err := NewHTTPClient(TransportConfig{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("expected request to succeed: %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestNewHTTPClient_InvalidConfig(t *testing.T) {
+	testCases := map[string]TransportConfig{
+		"invalid CA PEM":      {CACertPEM: "not a certificate"},
+		"missing CA file":     {CACertFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"client cert w/o key": {ClientCertPEM: "cert"},
+		"invalid client pair": {ClientCertPEM: "cert", ClientKeyPEM: "key"},
+		"proxy without host":  {ProxyURL: "proxy.local"},
+	}
+
+	for name, cfg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewHTTPClient(cfg); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
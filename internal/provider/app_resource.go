@@ -0,0 +1,332 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &AppResource{}
+var _ resource.ResourceWithValidateConfig = &AppResource{}
+var _ resource.ResourceWithImportState = &AppResource{}
+
+func NewAppResource() resource.Resource {
+	return &AppResource{}
+}
+
+// AppResource defines the resource implementation.
+type AppResource struct {
+	client Client
+}
+
+// AppResourceModel describes the resource data model.
+type AppResourceModel struct {
+	ID            types.String                    `tfsdk:"id"`
+	Endpoint      *AppEndpointModel               `tfsdk:"endpoint"`
+	Operations    map[string]AppOperationModel    `tfsdk:"operations"`
+	Subscriptions map[string]AppSubscriptionModel `tfsdk:"subscriptions"`
+}
+
+// AppEndpointModel describes the backend of the app.
+type AppEndpointModel struct {
+	URL    types.String `tfsdk:"url"`
+	Type   types.String `tfsdk:"type"`
+	Secret types.String `tfsdk:"secret"`
+}
+
+// AppOperationModel describes a route served by the app.
+type AppOperationModel struct {
+	Method types.String   `tfsdk:"method"`
+	Path   []types.String `tfsdk:"path"`
+	Action types.String   `tfsdk:"action"`
+}
+
+// AppSubscriptionModel describes the handler of resource changes.
+type AppSubscriptionModel struct {
+	Handler types.String `tfsdk:"handler"`
+}
+
+func (r *AppResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_app"
+}
+
+func (r *AppResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an Aidbox `App`, which registers a custom backend and the operations and subscriptions it serves",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "App resource ID, e.g. `myorg.reports`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"endpoint": schema.SingleNestedAttribute{
+				MarkdownDescription: "Backend the requests are forwarded to",
+				Required:            true,
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						MarkdownDescription: "Base URL of the backend",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+						},
+					},
+					"type": schema.StringAttribute{
+						MarkdownDescription: "Protocol of the backend. Defaults to `http-rpc`",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("http-rpc"),
+						Validators: []validator.String{
+							stringvalidator.OneOf("http-rpc"),
+						},
+					},
+					"secret": schema.StringAttribute{
+						MarkdownDescription: "Secret the backend uses to authenticate Aidbox requests",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"operations": schema.MapNestedAttribute{
+				MarkdownDescription: "Operations served by the app, keyed by operation ID",
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"method": operationMethodSchema(),
+						"path":   operationPathSchema(),
+						"action": schema.StringAttribute{
+							MarkdownDescription: "Handler of the operation. Defaults to the operation ID",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"subscriptions": schema.MapNestedAttribute{
+				MarkdownDescription: "Resource changes forwarded to the app, keyed by resource type",
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(resourceTypeRegexp, "must be a resource type, e.g. Patient")),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"handler": schema.StringAttribute{
+							MarkdownDescription: "Name of the handler called by Aidbox",
+							Required:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig rejects operations sharing a route, which Aidbox would
+// silently resolve to one of them.
+func (r *AppResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var operations types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("operations"), &operations)...)
+	if resp.Diagnostics.HasError() || operations.IsNull() || operations.IsUnknown() {
+		return
+	}
+
+	var models map[string]AppOperationModel
+	if diags := operations.ElementsAs(ctx, &models, false); diags.HasError() {
+		// Unknown values are checked at apply time.
+		return
+	}
+
+	ids := make([]string, 0, len(models))
+	for id := range models {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	routes := map[string]string{}
+	for _, id := range ids {
+		op := models[id]
+		if op.Method.IsUnknown() {
+			continue
+		}
+		route := operationRoute(op.Method.ValueString(), op.Path)
+		if other, ok := routes[route]; ok {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("operations").AtMapKey(id),
+				"Duplicate Operation Route",
+				fmt.Sprintf("Operations %q and %q both serve %s.", other, id, route),
+			)
+			continue
+		}
+		routes[route] = id
+	}
+}
+
+func (r *AppResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *AppResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model AppResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *AppResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model AppResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var app aidboxclient.App
+	err := r.client.GetResource(ctx, "App", model.ID.ValueString(), &app)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "App not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch App", fmt.Sprintf("Unable to fetch App %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapAppToModel(&model, app)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *AppResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model AppResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *AppResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model AppResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "App", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete App",
+			fmt.Sprintf("Error while trying to delete the App with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *AppResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *AppResource) save(ctx context.Context, model AppResourceModel, diags *diag.Diagnostics) {
+	app := aidboxclient.App{
+		ResourceType: "App",
+		ID:           model.ID.ValueString(),
+		APIVersion:   1,
+		Type:         "app",
+		Endpoint: aidboxclient.AppEndpoint{
+			URL:    model.Endpoint.URL.ValueString(),
+			Type:   model.Endpoint.Type.ValueString(),
+			Secret: model.Endpoint.Secret.ValueString(),
+		},
+	}
+
+	if len(model.Operations) > 0 {
+		app.Operations = make(map[string]aidboxclient.AppOperation, len(model.Operations))
+		for id, op := range model.Operations {
+			app.Operations[id] = aidboxclient.AppOperation{
+				Method: op.Method.ValueString(),
+				Path:   operationPathFromModel(op.Path),
+				Action: op.Action.ValueString(),
+			}
+		}
+	}
+	if len(model.Subscriptions) > 0 {
+		app.Subscriptions = make(map[string]aidboxclient.AppSubscription, len(model.Subscriptions))
+		for resourceType, s := range model.Subscriptions {
+			app.Subscriptions[resourceType] = aidboxclient.AppSubscription{Handler: s.Handler.ValueString()}
+		}
+	}
+
+	if err := r.client.PutResource(ctx, "App", app.ID, app, nil); err != nil {
+		diags.AddError("Failed to Save App", fmt.Sprintf("Unable to save App %s: %s", app.ID, err))
+	}
+}
+
+func mapAppToModel(model *AppResourceModel, app aidboxclient.App) {
+	secret := types.StringNull()
+	if model.Endpoint != nil {
+		secret = model.Endpoint.Secret
+	}
+	model.Endpoint = &AppEndpointModel{
+		URL:    types.StringValue(app.Endpoint.URL),
+		Type:   types.StringValue(app.Endpoint.Type),
+		Secret: preserveSecret(secret, app.Endpoint.Secret),
+	}
+
+	model.Operations = nil
+	if len(app.Operations) > 0 {
+		model.Operations = make(map[string]AppOperationModel, len(app.Operations))
+		for id, op := range app.Operations {
+			model.Operations[id] = AppOperationModel{
+				Method: types.StringValue(op.Method),
+				Path:   operationPathToModel(op.Path),
+				Action: optionalStringValue(op.Action),
+			}
+		}
+	}
+
+	model.Subscriptions = nil
+	if len(app.Subscriptions) > 0 {
+		model.Subscriptions = make(map[string]AppSubscriptionModel, len(app.Subscriptions))
+		for resourceType, s := range app.Subscriptions {
+			model.Subscriptions[resourceType] = AppSubscriptionModel{Handler: types.StringValue(s.Handler)}
+		}
+	}
+}
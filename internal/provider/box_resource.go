@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BoxResource{}
+var _ resource.ResourceWithModifyPlan = &BoxResource{}
+
+// boxIDRegexp matches box IDs, which are used as subdomains and database
+// names.
+var boxIDRegexp = regexp.MustCompile(`^[a-z][a-z0-9-]{0,62}$`)
+
+func NewBoxResource() resource.Resource {
+	return &BoxResource{}
+}
+
+// BoxResource defines the resource implementation.
+type BoxResource struct {
+	client     Client
+	serverInfo func(context.Context) *aidboxclient.ServerInfo
+}
+
+// BoxResourceModel describes the resource data model.
+type BoxResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Description       types.String `tfsdk:"description"`
+	FHIRVersion       types.String `tfsdk:"fhir_version"`
+	AdminClientID     types.String `tfsdk:"admin_client_id"`
+	AdminClientSecret types.String `tfsdk:"admin_client_secret"`
+	URL               types.String `tfsdk:"url"`
+}
+
+func (r *BoxResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_box"
+}
+
+func (r *BoxResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a tenant box of an Aidbox Multibox server, configured as the provider instance. " +
+			"`url`, `admin_client_id` and `admin_client_secret` can configure a provider alias that manages the box. " +
+			"Boxes cannot be updated in place: changing any attribute replaces the box and its data",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Box ID, also used as the subdomain of the box",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(boxIDRegexp, "must start with a lowercase letter and contain only lowercase letters, digits and dashes"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"fhir_version": schema.StringAttribute{
+				MarkdownDescription: "FHIR version of the box, e.g. `4.0.1`. Defaults to the server default",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"admin_client_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the root client of the box. Defaults to `root`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("root"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"admin_client_secret": schema.StringAttribute{
+				MarkdownDescription: "Secret of the root client of the box. Generated when not set",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL of the box",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *BoxResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+	r.serverInfo = data.ServerInfo
+}
+
+func (r *BoxResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(requireServerFeatureOnCreate(ctx, req, r.serverInfo, aidboxclient.FeatureMultibox, "aidbox_box")...)
+}
+
+func (r *BoxResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model BoxResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if model.AdminClientSecret.IsUnknown() || model.AdminClientSecret.IsNull() {
+		secret, err := generateSecret()
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to Generate Secret", fmt.Sprintf("Unable to generate the admin client secret: %s", err))
+			return
+		}
+		model.AdminClientSecret = types.StringValue(secret)
+	}
+
+	box, err := r.client.CreateBox(ctx, aidboxclient.Box{
+		ID:          model.ID.ValueString(),
+		Description: model.Description.ValueString(),
+		FHIRVersion: model.FHIRVersion.ValueString(),
+		Env: map[string]string{
+			"BOX_ROOT_CLIENT_ID":     model.AdminClientID.ValueString(),
+			"BOX_ROOT_CLIENT_SECRET": model.AdminClientSecret.ValueString(),
+		},
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Create Box", fmt.Sprintf("Unable to create box %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	boxURL, err := r.client.BoxURL(box)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Create Box", fmt.Sprintf("Unable to determine the URL of box %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	model.URL = types.StringValue(boxURL)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *BoxResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model BoxResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	box, err := r.client.GetBox(ctx, model.ID.ValueString())
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Box not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Box", fmt.Sprintf("Unable to fetch box %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	// The admin credentials are only known to the box itself and are kept
+	// as configured.
+	if box.BoxURL != "" {
+		model.URL = types.StringValue(box.BoxURL)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *BoxResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All configurable attributes require replacement.
+	var model BoxResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *BoxResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model BoxResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteBox(ctx, model.ID.ValueString()); err != nil && !aidboxclient.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Box",
+			fmt.Sprintf("Error while trying to delete the box with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+// generateSecret returns a random 256-bit secret, hex-encoded.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
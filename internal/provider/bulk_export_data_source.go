@@ -0,0 +1,287 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/datasource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &BulkExportDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &BulkExportDataSource{}
+
+const (
+	bulkExportPollInterval    = 5 * time.Second
+	bulkExportMaxPollInterval = time.Minute
+	bulkExportTimeout         = time.Hour
+)
+
+func NewBulkExportDataSource() datasource.DataSource {
+	return &BulkExportDataSource{}
+}
+
+// BulkExportDataSource defines the data source implementation.
+type BulkExportDataSource struct {
+	client Client
+}
+
+// BulkExportDataSourceModel describes the data source data model.
+type BulkExportDataSourceModel struct {
+	Level           types.String          `tfsdk:"level"`
+	GroupID         types.String          `tfsdk:"group_id"`
+	Types           []types.String        `tfsdk:"types"`
+	Since           types.String          `tfsdk:"since"`
+	OutputDir       types.String          `tfsdk:"output_dir"`
+	TransactionTime types.String          `tfsdk:"transaction_time"`
+	Files           []BulkExportFileModel `tfsdk:"files"`
+	ErrorFiles      []BulkExportFileModel `tfsdk:"error_files"`
+	Timeouts        timeouts.Value        `tfsdk:"timeouts"`
+}
+
+// BulkExportFileModel describes an exported NDJSON file.
+type BulkExportFileModel struct {
+	Type  types.String `tfsdk:"type"`
+	URL   types.String `tfsdk:"url"`
+	Count types.Int64  `tfsdk:"count"`
+	Path  types.String `tfsdk:"path"`
+}
+
+func (d *BulkExportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_export"
+}
+
+func bulkExportFileSchema(description string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: description,
+		Computed:            true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"type": schema.StringAttribute{
+					MarkdownDescription: "Resource type of the file",
+					Computed:            true,
+				},
+				"url": schema.StringAttribute{
+					Computed: true,
+				},
+				"count": schema.Int64Attribute{
+					MarkdownDescription: "Number of resources in the file, when reported by the server",
+					Computed:            true,
+				},
+				"path": schema.StringAttribute{
+					MarkdownDescription: "Local path of the file, when `output_dir` is set",
+					Computed:            true,
+				},
+			},
+		},
+	}
+}
+
+func (d *BulkExportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Runs a FHIR bulk `$export`, waits for it to complete and returns the manifest of the exported files, " +
+			"optionally downloading them. A new export runs every time the data source is read, i.e. on every plan",
+		Attributes: map[string]schema.Attribute{
+			"level": schema.StringAttribute{
+				MarkdownDescription: "`system`, `patient` or `group`. Defaults to `system`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(aidboxclient.BulkExportSystem, aidboxclient.BulkExportPatient, aidboxclient.BulkExportGroup),
+				},
+			},
+			"group_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Group to export. Required when `level` is `group`",
+				Optional:            true,
+			},
+			"types": schema.ListAttribute{
+				MarkdownDescription: "Resource types to export (`_type`). Defaults to all",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"since": schema.StringAttribute{
+				MarkdownDescription: "Only export resources changed after this RFC 3339 timestamp (`_since`)",
+				Optional:            true,
+			},
+			"output_dir": schema.StringAttribute{
+				MarkdownDescription: "Local directory to download the files into, as `<type>-<n>.ndjson`",
+				Optional:            true,
+			},
+			"transaction_time": schema.StringAttribute{
+				MarkdownDescription: "Time of the snapshot",
+				Computed:            true,
+			},
+			"files":       bulkExportFileSchema("Exported files"),
+			"error_files": bulkExportFileSchema("Files of `OperationOutcome` resources describing export failures"),
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx),
+		},
+	}
+}
+
+func (d *BulkExportDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var level, groupID, since types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("level"), &level)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("group_id"), &groupID)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("since"), &since)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !level.IsUnknown() && !groupID.IsUnknown() {
+		isGroup := level.ValueString() == aidboxclient.BulkExportGroup
+		if isGroup && groupID.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("group_id"), "Missing Attribute", "'group_id' is required when 'level' is \"group\".")
+		}
+		if !isGroup && !groupID.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("group_id"), "Invalid Attribute", "'group_id' is only supported when 'level' is \"group\".")
+		}
+	}
+
+	if !since.IsNull() && !since.IsUnknown() {
+		if _, err := time.Parse(time.RFC3339, since.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("since"), "Invalid Timestamp", fmt.Sprintf("'since' must be an RFC 3339 timestamp: %s", err))
+		}
+	}
+}
+
+func (d *BulkExportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *BulkExportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model BulkExportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Read(ctx, bulkExportTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	exportReq := aidboxclient.BulkExportRequest{
+		Level:   model.Level.ValueString(),
+		GroupID: model.GroupID.ValueString(),
+		Since:   model.Since.ValueString(),
+	}
+	for _, t := range model.Types {
+		exportReq.Types = append(exportReq.Types, t.ValueString())
+	}
+
+	statusURL, err := d.client.StartBulkExport(ctx, exportReq)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Export Data", fmt.Sprintf("Unable to start the export: %s", err))
+		return
+	}
+
+	status, err := d.client.WaitAsync(ctx, statusURL, aidboxclient.PollOptions{
+		Description: "bulk export",
+		Interval:    bulkExportPollInterval,
+		Backoff:     1.5,
+		MaxInterval: bulkExportMaxPollInterval,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Export Data", fmt.Sprintf("Export did not complete: %s", err))
+		return
+	}
+
+	manifest, err := aidboxclient.ParseBulkExportManifest(status.Body)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Export Data", err.Error())
+		return
+	}
+
+	model.TransactionTime = optionalStringValue(manifest.TransactionTime)
+	model.Files = d.files(ctx, manifest.Output, manifest.RequiresAccessToken, model.OutputDir, "", &resp.Diagnostics)
+	model.ErrorFiles = d.files(ctx, manifest.Error, manifest.RequiresAccessToken, model.OutputDir, "error-", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(manifest.Error) > 0 {
+		resp.Diagnostics.AddWarning("Partial Export Failure", fmt.Sprintf("The export reported %d error files, see error_files.", len(manifest.Error)))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// files maps files of the manifest to the model and downloads them when
+// outputDir is set.
+func (d *BulkExportDataSource) files(ctx context.Context, files []aidboxclient.BulkExportFile, requiresAccessToken bool, outputDir types.String, prefix string, diags *diag.Diagnostics) []BulkExportFileModel {
+	models := make([]BulkExportFileModel, 0, len(files))
+	seen := map[string]int{}
+	for _, f := range files {
+		m := BulkExportFileModel{
+			Type:  types.StringValue(f.Type),
+			URL:   types.StringValue(f.URL),
+			Count: types.Int64PointerValue(f.Count),
+			Path:  types.StringNull(),
+		}
+
+		if !outputDir.IsNull() {
+			seen[f.Type]++
+			name, err := bulkExportFileName(outputDir.ValueString(), prefix, f.Type, seen[f.Type])
+			if err != nil {
+				diags.AddError("Invalid Export Manifest", err.Error())
+				return nil
+			}
+			if err := d.download(ctx, f.URL, requiresAccessToken, name); err != nil {
+				diags.AddAttributeError(path.Root("output_dir"), "Failed to Download Export File", fmt.Sprintf("Unable to download %s: %s", f.URL, err))
+				return nil
+			}
+			m.Path = types.StringValue(name)
+		}
+		models = append(models, m)
+	}
+	return models
+}
+
+// bulkExportFileName returns the local path of the nth file of a type. The
+// type comes from the server and must not escape outputDir.
+func bulkExportFileName(outputDir, prefix, resourceType string, n int) (string, error) {
+	if !resourceTypeRegexp.MatchString(resourceType) {
+		return "", fmt.Errorf("the manifest lists a file of invalid resource type %q", resourceType)
+	}
+	return filepath.Join(outputDir, fmt.Sprintf("%s%s-%d.ndjson", prefix, resourceType, n)), nil
+}
+
+func (d *BulkExportDataSource) download(ctx context.Context, fileURL string, requiresAccessToken bool, name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tflog.Info(ctx, "Downloading export file", map[string]interface{}{"url": fileURL, "path": name})
+	if err := d.client.DownloadExportFile(ctx, fileURL, requiresAccessToken, f); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,22 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBulkExportFileName(t *testing.T) {
+	name, err := bulkExportFileName("export", "error-", "OperationOutcome", 2)
+	if err != nil || name != filepath.Join("export", "error-OperationOutcome-2.ndjson") {
+		t.Errorf("unexpected file name %q, %v", name, err)
+	}
+
+	for _, resourceType := range []string{"../Patient", "Patient/../../etc", "..", "", "patient"} {
+		if name, err := bulkExportFileName("export", "", resourceType, 1); err == nil {
+			t.Errorf("%q: expected an error, got %q", resourceType, name)
+		}
+	}
+}
@@ -0,0 +1,438 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &BulkImportResource{}
+var _ resource.ResourceWithValidateConfig = &BulkImportResource{}
+var _ resource.ResourceWithModifyPlan = &BulkImportResource{}
+
+const (
+	bulkImportModeImport = "import"
+	bulkImportModeLoad   = "load"
+
+	bulkImportChunkSize       = 500
+	bulkImportPollInterval    = 5 * time.Second
+	bulkImportMaxPollInterval = time.Minute
+	bulkImportTimeout         = time.Hour
+)
+
+func NewBulkImportResource() resource.Resource {
+	return &BulkImportResource{}
+}
+
+// BulkImportResource defines the resource implementation.
+type BulkImportResource struct {
+	client Client
+}
+
+// BulkImportResourceModel describes the resource data model.
+type BulkImportResourceModel struct {
+	ID              types.String           `tfsdk:"id"`
+	Mode            types.String           `tfsdk:"mode"`
+	ContentEncoding types.String           `tfsdk:"content_encoding"`
+	Inputs          []BulkImportInputModel `tfsdk:"inputs"`
+	Triggers        types.Map              `tfsdk:"triggers"`
+	SourceSHA256    types.String           `tfsdk:"source_sha256"`
+	ResourceCounts  types.Map              `tfsdk:"resource_counts"`
+	Timeouts        timeouts.Value         `tfsdk:"timeouts"`
+}
+
+// BulkImportInputModel describes an NDJSON file to import.
+type BulkImportInputModel struct {
+	ResourceType types.String `tfsdk:"resource_type"`
+	URL          types.String `tfsdk:"url"`
+	File         types.String `tfsdk:"file"`
+}
+
+func (r *BulkImportResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bulk_import"
+}
+
+func (r *BulkImportResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Loads NDJSON datasets into an Aidbox instance once, from URLs the instance downloads or from local files. " +
+			"Changing the inputs, the content of a local file or `triggers` loads the data again. " +
+			"Resources are loaded under their `id`; resources without one are created anew each time the data is loaded. " +
+			"Destroying the resource does not delete the loaded resources",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Import ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mode": schema.StringAttribute{
+				MarkdownDescription: "How URL inputs are loaded: `import` runs a validating FHIR `$import`, `load` runs the faster, " +
+					"non-validating `$load`. Local files are always uploaded in batches. Defaults to `import`",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(bulkImportModeImport),
+				Validators: []validator.String{
+					stringvalidator.OneOf(bulkImportModeImport, bulkImportModeLoad),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content_encoding": schema.StringAttribute{
+				MarkdownDescription: "Encoding of the URL inputs of an `import`: `gzip` or `identity`. Defaults to `gzip`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("gzip"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("gzip", "identity"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"inputs": schema.ListNestedAttribute{
+				MarkdownDescription: "NDJSON files to load",
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource_type": schema.StringAttribute{
+							MarkdownDescription: "Resource type of the file. Required for URL inputs in `import` mode",
+							Optional:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "URL the instance downloads the file from. Conflicts with `file`",
+							Optional:            true,
+							Validators: []validator.String{
+								stringvalidator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("file")),
+							},
+						},
+						"file": schema.StringAttribute{
+							MarkdownDescription: "Path to a local `.ndjson` or `.ndjson.gz` file to upload. Conflicts with `url`",
+							Optional:            true,
+						},
+					},
+				},
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that load the data again when they change",
+				ElementType:         types.StringType,
+				Optional:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of the local files, used to detect changes to them",
+				Computed:            true,
+			},
+			"resource_counts": schema.MapAttribute{
+				MarkdownDescription: "Number of resources loaded per resource type",
+				ElementType:         types.Int64Type,
+				Computed:            true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				CreateDescription: "How long to wait for the import. Defaults to `1h`",
+			}),
+		},
+	}
+}
+
+func (r *BulkImportResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var mode types.String
+	var inputs types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("mode"), &mode)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("inputs"), &inputs)...)
+	if resp.Diagnostics.HasError() || mode.IsUnknown() || mode.ValueString() == bulkImportModeLoad || inputs.IsNull() || inputs.IsUnknown() {
+		return
+	}
+
+	var models []BulkImportInputModel
+	resp.Diagnostics.Append(inputs.ElementsAs(ctx, &models, false)...)
+	for i, input := range models {
+		if !input.URL.IsNull() && input.ResourceType.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("inputs").AtListIndex(i).AtName("resource_type"),
+				"Missing Attribute",
+				"'resource_type' is required for URL inputs when 'mode' is \"import\".",
+			)
+		}
+	}
+}
+
+func (r *BulkImportResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+// ModifyPlan computes the checksum of the local files, so that changed files
+// plan a new import.
+func (r *BulkImportResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var inputs types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("inputs"), &inputs)...)
+	if resp.Diagnostics.HasError() || inputs.IsUnknown() {
+		return
+	}
+	var models []BulkImportInputModel
+	resp.Diagnostics.Append(inputs.ElementsAs(ctx, &models, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var files []string
+	for _, input := range models {
+		if input.File.IsUnknown() {
+			return
+		}
+		if !input.File.IsNull() {
+			files = append(files, input.File.ValueString())
+		}
+	}
+
+	checksum, err := bulkImportSourceSHA256(files)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("inputs"), "Failed to Read Import File", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_sha256"), checksum)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var prior types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("source_sha256"), &prior)...)
+	if !prior.Equal(checksum) {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("source_sha256"))
+	}
+}
+
+func (r *BulkImportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model BulkImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Create(ctx, bulkImportTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	secret, err := generateSecret()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Generate Import ID", err.Error())
+		return
+	}
+	model.ID = types.StringValue("tf-import-" + secret[:16])
+
+	result, err := r.load(ctx, model)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Import Data",
+			fmt.Sprintf("Import %s failed after loading %d resources: %s\n\n"+
+				"The import is saved as tainted, so that it is loaded again on the next apply. Resources without an id "+
+				"are created again; run terraform untaint to keep the partially loaded data instead.",
+				model.ID.ValueString(), result.Total(), err),
+		)
+		// Nothing to keep when nothing was loaded.
+		if result.Total() == 0 {
+			return
+		}
+	} else {
+		resp.Diagnostics.Append(bulkImportResultDiagnostics(result)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	counts := result.Counts
+	if counts == nil {
+		counts = map[string]int64{}
+	}
+	model.ResourceCounts, diags = types.MapValueFrom(ctx, types.Int64Type, counts)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// bulkImportSourceSHA256 returns the checksum of the local files of an
+// import, or null when it has none.
+func bulkImportSourceSHA256(files []string) (types.String, error) {
+	if len(files) == 0 {
+		return types.StringNull(), nil
+	}
+
+	h := sha256.New()
+	for _, file := range files {
+		sum, err := fileSHA256(file)
+		if err != nil {
+			return types.StringNull(), err
+		}
+		fmt.Fprintf(h, "%s  %s\n", sum, file)
+	}
+	return types.StringValue(hex.EncodeToString(h.Sum(nil))), nil
+}
+
+// bulkImportResultDiagnostics reports the failures of an import. Partial
+// failures are warnings, so that the loaded data is not imported again on
+// the next apply; they are errors when nothing was loaded.
+func bulkImportResultDiagnostics(result aidboxclient.BulkImportResult) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, e := range result.Errors {
+		detail := fmt.Sprintf("%d %s resources failed to load: %s", e.Count, e.ResourceType, e.Message)
+		if result.Total() == 0 {
+			diags.AddError("Failed to Import Data", detail)
+		} else {
+			diags.AddWarning("Partial Import Failure", detail)
+		}
+	}
+	return diags
+}
+
+func (r *BulkImportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// The import is a one-time action: the loaded resources are not tracked,
+	// so the state is kept as is.
+}
+
+func (r *BulkImportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All attributes but the timeouts require replacement.
+	var model BulkImportResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *BulkImportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	tflog.Info(ctx, "Removing bulk import from state, the loaded resources are kept")
+}
+
+// load loads the URL inputs through the instance and uploads the local
+// files, and returns the combined result. On failure, the result has what
+// was loaded before.
+func (r *BulkImportResource) load(ctx context.Context, model BulkImportResourceModel) (aidboxclient.BulkImportResult, error) {
+	var result aidboxclient.BulkImportResult
+	var urls []aidboxclient.BulkImportInput
+	for _, input := range model.Inputs {
+		if !input.URL.IsNull() {
+			urls = append(urls, aidboxclient.BulkImportInput{ResourceType: input.ResourceType.ValueString(), URL: input.URL.ValueString()})
+		}
+	}
+
+	if len(urls) > 0 && model.Mode.ValueString() == bulkImportModeImport {
+		imported, err := r.bulkImport(ctx, model, urls)
+		result.Merge(imported)
+		if err != nil {
+			return result, err
+		}
+	} else {
+		for _, input := range urls {
+			tflog.Info(ctx, "Loading NDJSON", map[string]interface{}{"url": input.URL, "resource_type": input.ResourceType})
+			loaded, err := r.client.LoadNDJSON(ctx, input.URL, input.ResourceType)
+			result.Merge(loaded)
+			if err != nil {
+				return result, fmt.Errorf("failed to load %s: %w", input.URL, err)
+			}
+		}
+	}
+
+	for _, input := range model.Inputs {
+		if input.File.IsNull() {
+			continue
+		}
+		uploaded, err := r.upload(ctx, input.File.ValueString())
+		result.Merge(uploaded)
+		if err != nil {
+			return result, fmt.Errorf("failed to upload %s: %w", input.File.ValueString(), err)
+		}
+	}
+	return result, nil
+}
+
+func (r *BulkImportResource) bulkImport(ctx context.Context, model BulkImportResourceModel, inputs []aidboxclient.BulkImportInput) (aidboxclient.BulkImportResult, error) {
+	encoding := model.ContentEncoding.ValueString()
+	if encoding == "identity" {
+		encoding = ""
+	}
+
+	tflog.Info(ctx, "Starting bulk import", map[string]interface{}{"id": model.ID.ValueString(), "inputs": len(inputs)})
+	statusURL, result, err := r.client.StartBulkImport(ctx, model.ID.ValueString(), encoding, inputs)
+	if err != nil || statusURL == "" {
+		return result, err
+	}
+
+	status, err := r.client.WaitAsync(ctx, statusURL, aidboxclient.PollOptions{
+		Description: "bulk import " + model.ID.ValueString(),
+		Interval:    bulkImportPollInterval,
+		Backoff:     1.5,
+		MaxInterval: bulkImportMaxPollInterval,
+	})
+	if err != nil {
+		return result, err
+	}
+	return aidboxclient.ParseBulkImportResult(status.Body)
+}
+
+func (r *BulkImportResource) upload(ctx context.Context, file string) (aidboxclient.BulkImportResult, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return aidboxclient.BulkImportResult{}, err
+	}
+	defer f.Close()
+
+	tflog.Info(ctx, "Uploading NDJSON", map[string]interface{}{"file": file})
+	return r.client.ImportNDJSON(ctx, f, bulkImportChunkSize)
+}
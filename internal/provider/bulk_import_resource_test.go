@@ -0,0 +1,216 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestBulkImportSourceSHA256(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "patients.ndjson")
+	if err := os.WriteFile(file, []byte(`{"resourceType": "Patient"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	none, err := bulkImportSourceSHA256(nil)
+	if err != nil || !none.IsNull() {
+		t.Errorf("expected a null checksum without files, got %s, %v", none, err)
+	}
+
+	before, err := bulkImportSourceSHA256([]string{file})
+	if err != nil || before.IsNull() {
+		t.Fatalf("expected a checksum, got %s, %v", before, err)
+	}
+	if again, _ := bulkImportSourceSHA256([]string{file}); !again.Equal(before) {
+		t.Error("expected a stable checksum")
+	}
+
+	if err := os.WriteFile(file, []byte(`{"resourceType": "Patient", "id": "pt-1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := bulkImportSourceSHA256([]string{file}); after.Equal(before) {
+		t.Error("expected the checksum to change with the file")
+	}
+
+	if _, err := bulkImportSourceSHA256([]string{filepath.Join(dir, "missing.ndjson")}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestBulkImportModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &BulkImportResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	inputsType := objectType.AttributeTypes["inputs"].(tftypes.List)
+	inputType := inputsType.ElementType.(tftypes.Object)
+
+	file := filepath.Join(t.TempDir(), "patients.ndjson")
+	value := func(sourceSHA256 interface{}) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attrType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+		values["id"] = tftypes.NewValue(tftypes.String, "tf-import-1")
+		values["mode"] = tftypes.NewValue(tftypes.String, "import")
+		values["source_sha256"] = tftypes.NewValue(tftypes.String, sourceSHA256)
+		values["inputs"] = tftypes.NewValue(inputsType, []tftypes.Value{
+			tftypes.NewValue(inputType, map[string]tftypes.Value{
+				"resource_type": tftypes.NewValue(tftypes.String, "Patient"),
+				"url":           tftypes.NewValue(tftypes.String, nil),
+				"file":          tftypes.NewValue(tftypes.String, file),
+			}),
+		})
+		return tftypes.NewValue(objectType, values)
+	}
+	modifyPlan := func(state tftypes.Value) resource.ModifyPlanResponse {
+		req := resource.ModifyPlanRequest{
+			State: tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+			Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: value(tftypes.UnknownValue)},
+		}
+		resp := resource.ModifyPlanResponse{Plan: req.Plan}
+		r.ModifyPlan(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		return resp
+	}
+
+	if err := os.WriteFile(file, []byte(`{"resourceType": "Patient"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checksum, _ := bulkImportSourceSHA256([]string{file})
+
+	resp := modifyPlan(tftypes.NewValue(objectType, nil))
+	var planned BulkImportResourceModel
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &planned)...)
+	if !planned.SourceSHA256.Equal(checksum) {
+		t.Errorf("expected source_sha256 %s, got %s", checksum, planned.SourceSHA256)
+	}
+
+	resp = modifyPlan(value(checksum.ValueString()))
+	if len(resp.RequiresReplace) != 0 {
+		t.Errorf("expected no replacement for unchanged files, got %v", resp.RequiresReplace)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"resourceType": "Patient", "id": "pt-1"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resp = modifyPlan(value(checksum.ValueString()))
+	if !resp.RequiresReplace.Contains(path.Root("source_sha256")) {
+		t.Errorf("expected a replacement for changed files, got %v", resp.RequiresReplace)
+	}
+}
+
+func TestBulkImportCreate(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "observations.ndjson")
+	if err := os.WriteFile(file, []byte(`{"resourceType": "Observation"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := aidboxclient.BulkImportError{ResourceType: "Observation", Count: 2, Message: "invalid reference"}
+	testCases := map[string]struct {
+		result    aidboxclient.BulkImportResult
+		err       error
+		expectErr bool
+		warnings  int
+		counts    map[string]int64
+	}{
+		"success": {
+			result: aidboxclient.BulkImportResult{Counts: map[string]int64{"Observation": 10}},
+			counts: map[string]int64{"Observation": 10},
+		},
+		"partial failure": {
+			result:   aidboxclient.BulkImportResult{Counts: map[string]int64{"Observation": 10}, Errors: []aidboxclient.BulkImportError{failure}},
+			warnings: 1,
+			counts:   map[string]int64{"Observation": 10},
+		},
+		"all failed": {
+			result:    aidboxclient.BulkImportResult{Errors: []aidboxclient.BulkImportError{failure}},
+			expectErr: true,
+		},
+		"interrupted": {
+			result:    aidboxclient.BulkImportResult{Counts: map[string]int64{"Observation": 500}},
+			err:       errors.New("connection reset"),
+			expectErr: true,
+			counts:    map[string]int64{"Observation": 500},
+		},
+		"interrupted before loading": {
+			err:       errors.New("connection refused"),
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient()
+			client.importNDJSON = func(ctx context.Context, content io.Reader, chunkSize int) (aidboxclient.BulkImportResult, error) {
+				return tc.result, tc.err
+			}
+			r := &BulkImportResource{client: client}
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+			objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+			inputsType := objectType.AttributeTypes["inputs"].(tftypes.List)
+
+			values := map[string]tftypes.Value{}
+			for name, attrType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+			values["id"] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+			values["mode"] = tftypes.NewValue(tftypes.String, "import")
+			values["content_encoding"] = tftypes.NewValue(tftypes.String, "gzip")
+			values["resource_counts"] = tftypes.NewValue(objectType.AttributeTypes["resource_counts"], tftypes.UnknownValue)
+			values["inputs"] = tftypes.NewValue(inputsType, []tftypes.Value{
+				tftypes.NewValue(inputsType.ElementType, map[string]tftypes.Value{
+					"resource_type": tftypes.NewValue(tftypes.String, "Observation"),
+					"url":           tftypes.NewValue(tftypes.String, nil),
+					"file":          tftypes.NewValue(tftypes.String, file),
+				}),
+			})
+
+			req := resource.CreateRequest{Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}}
+			resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+			r.Create(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() != tc.expectErr {
+				t.Fatalf("expected error: %t, got diagnostics: %v", tc.expectErr, resp.Diagnostics)
+			}
+			if got := len(resp.Diagnostics.Warnings()); got != tc.warnings {
+				t.Errorf("expected %d warnings, got %v", tc.warnings, resp.Diagnostics)
+			}
+
+			// Loaded data is kept in state, even when the import failed
+			// halfway, so that it is not silently loaded again.
+			if tc.counts == nil {
+				if !resp.State.Raw.IsNull() {
+					t.Errorf("expected no state, got %s", resp.State.Raw)
+				}
+				return
+			}
+			var model BulkImportResourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+			var counts map[string]int64
+			resp.Diagnostics.Append(model.ResourceCounts.ElementsAs(ctx, &counts, false)...)
+			if !reflect.DeepEqual(counts, tc.counts) || model.ID.IsUnknown() {
+				t.Errorf("expected counts %v, got %v", tc.counts, counts)
+			}
+		})
+	}
+}
@@ -0,0 +1,406 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &CodeSystemResource{}
+var _ resource.ResourceWithConfigValidators = &CodeSystemResource{}
+var _ resource.ResourceWithModifyPlan = &CodeSystemResource{}
+var _ resource.ResourceWithImportState = &CodeSystemResource{}
+
+var publicationStatuses = []string{"draft", "active", "retired", "unknown"}
+
+func NewCodeSystemResource() resource.Resource {
+	return &CodeSystemResource{}
+}
+
+// CodeSystemResource defines the resource implementation.
+type CodeSystemResource struct {
+	client Client
+}
+
+// CodeSystemResourceModel describes the resource data model.
+type CodeSystemResourceModel struct {
+	ID             types.String             `tfsdk:"id"`
+	URL            types.String             `tfsdk:"url"`
+	Name           types.String             `tfsdk:"name"`
+	Version        types.String             `tfsdk:"version"`
+	Status         types.String             `tfsdk:"status"`
+	Content        types.String             `tfsdk:"content"`
+	Concepts       []CodeSystemConceptModel `tfsdk:"concepts"`
+	ConceptsCSV    types.String             `tfsdk:"concepts_csv"`
+	ConceptsSHA256 types.String             `tfsdk:"concepts_sha256"`
+	ChunkSize      types.Int64              `tfsdk:"chunk_size"`
+	ConceptCount   types.Int64              `tfsdk:"concept_count"`
+}
+
+// CodeSystemConceptModel describes a code of the code system.
+type CodeSystemConceptModel struct {
+	Code       types.String `tfsdk:"code"`
+	Display    types.String `tfsdk:"display"`
+	Definition types.String `tfsdk:"definition"`
+}
+
+func (r *CodeSystemResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_code_system"
+}
+
+func (r *CodeSystemResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a FHIR `CodeSystem` of an Aidbox instance. Concepts are given inline or in a CSV file " +
+			"and stored as Aidbox `Concept` resources, uploaded in batches of `chunk_size`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "CodeSystem resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL, used as the `system` of its codes",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				Optional: true,
+			},
+			"version": schema.StringAttribute{
+				Optional: true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Publication status. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(publicationStatuses...),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "How much of the code system is represented. Defaults to `complete`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("complete"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("not-present", "example", "fragment", "complete", "supplement"),
+				},
+			},
+			"concepts": schema.ListNestedAttribute{
+				MarkdownDescription: "Codes of the code system. Conflicts with `concepts_csv`",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"code": schema.StringAttribute{
+							Required: true,
+						},
+						"display": schema.StringAttribute{
+							Optional: true,
+						},
+						"definition": schema.StringAttribute{
+							Optional: true,
+						},
+					},
+				},
+			},
+			"concepts_csv": schema.StringAttribute{
+				MarkdownDescription: "Path to a CSV file with a header row and the columns `code`, `display` (optional) and `definition` (optional). Conflicts with `concepts`",
+				Optional:            true,
+			},
+			"concepts_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of `concepts_csv`, used to detect changes to the file",
+				Computed:            true,
+			},
+			"chunk_size": schema.Int64Attribute{
+				MarkdownDescription: "Number of concepts uploaded per batch request. Defaults to `1000`",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1000),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 10000),
+				},
+			},
+			"concept_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of concepts of this code system stored on the instance. Concepts of other code systems with the same `url` are not counted",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *CodeSystemResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(
+			path.MatchRoot("concepts"),
+			path.MatchRoot("concepts_csv"),
+		),
+	}
+}
+
+func (r *CodeSystemResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+// ModifyPlan plans the number of concepts and the checksum of the CSV file,
+// so that changed or missing concepts are uploaded again.
+func (r *CodeSystemResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var conceptsCSV types.String
+	var concepts types.List
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("concepts_csv"), &conceptsCSV)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("concepts"), &concepts)...)
+	if resp.Diagnostics.HasError() || conceptsCSV.IsUnknown() || concepts.IsUnknown() {
+		return
+	}
+
+	checksum := types.StringNull()
+	count := types.Int64Value(int64(len(concepts.Elements())))
+	if !conceptsCSV.IsNull() {
+		concepts, err := readCodeSystemCSV(conceptsCSV.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("concepts_csv"), "Invalid Concepts File", err.Error())
+			return
+		}
+		sum, err := fileSHA256(conceptsCSV.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("concepts_csv"), "Invalid Concepts File", err.Error())
+			return
+		}
+		checksum = types.StringValue(sum)
+		count = types.Int64Value(int64(len(concepts)))
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("concepts_sha256"), checksum)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("concept_count"), count)...)
+}
+
+func (r *CodeSystemResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model CodeSystemResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.uploadConcepts(ctx, &model, false, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *CodeSystemResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model CodeSystemResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cs aidboxclient.CodeSystem
+	err := r.client.GetResource(ctx, "fhir/CodeSystem", model.ID.ValueString(), &cs)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "CodeSystem not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch CodeSystem", fmt.Sprintf("Unable to fetch CodeSystem %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	count, err := r.client.CountConcepts(ctx, model.ID.ValueString(), cs.URL)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Count Concepts", fmt.Sprintf("Unable to count the concepts of CodeSystem %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	model.URL = types.StringValue(cs.URL)
+	model.Name = optionalStringValue(cs.Name)
+	model.Version = optionalStringValue(cs.Version)
+	model.Status = types.StringValue(cs.Status)
+	model.Content = types.StringValue(cs.Content)
+	model.ConceptCount = types.Int64Value(count)
+	if model.ChunkSize.IsNull() {
+		model.ChunkSize = types.Int64Value(1000)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *CodeSystemResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, state CodeSystemResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Concepts are only uploaded again when they or the system changed.
+	if model.URL.Equal(state.URL) &&
+		model.ConceptsSHA256.Equal(state.ConceptsSHA256) &&
+		model.ConceptCount.Equal(state.ConceptCount) &&
+		reflect.DeepEqual(model.Concepts, state.Concepts) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+		return
+	}
+
+	r.uploadConcepts(ctx, &model, true, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *CodeSystemResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model CodeSystemResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteConcepts(ctx, model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Concepts",
+			fmt.Sprintf("Error while trying to delete the concepts of CodeSystem %s: %s", model.ID.ValueString(), err),
+		)
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "fhir/CodeSystem", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete CodeSystem",
+			fmt.Sprintf("Error while trying to delete the CodeSystem with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *CodeSystemResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *CodeSystemResource) save(ctx context.Context, model CodeSystemResourceModel, diags *diag.Diagnostics) {
+	cs := aidboxclient.CodeSystem{
+		ResourceType: "CodeSystem",
+		ID:           model.ID.ValueString(),
+		URL:          model.URL.ValueString(),
+		Name:         model.Name.ValueString(),
+		Version:      model.Version.ValueString(),
+		Status:       model.Status.ValueString(),
+		Content:      model.Content.ValueString(),
+	}
+
+	if err := r.client.PutResource(ctx, "fhir/CodeSystem", cs.ID, cs, nil); err != nil {
+		diags.Append(operationOutcomeDiagnostics(err, path.Root("url"), "Failed to Save CodeSystem")...)
+	}
+}
+
+// uploadConcepts uploads the concepts of the code system, deleting the
+// previously uploaded ones first when replace is set.
+func (r *CodeSystemResource) uploadConcepts(ctx context.Context, model *CodeSystemResourceModel, replace bool, diags *diag.Diagnostics) {
+	id, system := model.ID.ValueString(), model.URL.ValueString()
+
+	var concepts []CodeSystemConceptModel
+	if model.ConceptsCSV.IsNull() {
+		concepts = model.Concepts
+	} else {
+		var err error
+		concepts, err = readCodeSystemCSV(model.ConceptsCSV.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("concepts_csv"), "Invalid Concepts File", err.Error())
+			return
+		}
+	}
+
+	if replace {
+		if err := r.client.DeleteConcepts(ctx, id); err != nil {
+			diags.AddError("Failed to Delete Concepts", fmt.Sprintf("Unable to delete the previous concepts of CodeSystem %s: %s", id, err))
+			return
+		}
+	}
+
+	resources := make([]aidboxclient.Concept, 0, len(concepts))
+	for _, c := range concepts {
+		resources = append(resources, aidboxclient.Concept{
+			ResourceType: "Concept",
+			ID:           aidboxclient.ConceptID(id, c.Code.ValueString()),
+			System:       system,
+			Code:         c.Code.ValueString(),
+			Display:      c.Display.ValueString(),
+			Definition:   c.Definition.ValueString(),
+		})
+	}
+
+	tflog.Info(ctx, "Uploading concepts", map[string]interface{}{"code_system": id, "count": len(resources)})
+	if err := r.client.UploadConcepts(ctx, resources, int(model.ChunkSize.ValueInt64())); err != nil {
+		diags.AddError("Failed to Upload Concepts", fmt.Sprintf("Unable to upload the concepts of CodeSystem %s: %s", id, err))
+		return
+	}
+
+	model.ConceptCount = types.Int64Value(int64(len(resources)))
+}
+
+// readCodeSystemCSV reads concepts from a CSV file with the columns code,
+// display and definition.
+func readCodeSystemCSV(name string) ([]CodeSystemConceptModel, error) {
+	records, err := readCSVRecords(name, "code")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	seen := make(map[string]bool, len(records))
+	concepts := make([]CodeSystemConceptModel, 0, len(records))
+	for _, record := range records {
+		if seen[record["code"]] {
+			return nil, fmt.Errorf("%s: duplicate code %q", name, record["code"])
+		}
+		seen[record["code"]] = true
+
+		concepts = append(concepts, CodeSystemConceptModel{
+			Code:       types.StringValue(record["code"]),
+			Display:    optionalStringValue(record["display"]),
+			Definition: optionalStringValue(record["definition"]),
+		})
+	}
+	return concepts, nil
+}
@@ -0,0 +1,464 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConceptMapResource{}
+var _ resource.ResourceWithConfigValidators = &ConceptMapResource{}
+var _ resource.ResourceWithModifyPlan = &ConceptMapResource{}
+var _ resource.ResourceWithImportState = &ConceptMapResource{}
+
+var conceptMapEquivalences = []string{"relatedto", "equivalent", "equal", "wider", "subsumes", "narrower", "specializes", "inexact", "unmatched", "disjoint"}
+
+func NewConceptMapResource() resource.Resource {
+	return &ConceptMapResource{}
+}
+
+// ConceptMapResource defines the resource implementation.
+type ConceptMapResource struct {
+	client Client
+}
+
+// ConceptMapResourceModel describes the resource data model.
+type ConceptMapResourceModel struct {
+	ID             types.String           `tfsdk:"id"`
+	URL            types.String           `tfsdk:"url"`
+	Name           types.String           `tfsdk:"name"`
+	Status         types.String           `tfsdk:"status"`
+	SourceValueSet types.String           `tfsdk:"source_value_set"`
+	TargetValueSet types.String           `tfsdk:"target_value_set"`
+	Groups         []ConceptMapGroupModel `tfsdk:"groups"`
+	MappingsCSV    types.String           `tfsdk:"mappings_csv"`
+	MappingsSHA256 types.String           `tfsdk:"mappings_sha256"`
+	MappingCount   types.Int64            `tfsdk:"mapping_count"`
+}
+
+// ConceptMapGroupModel describes the mappings between two code systems.
+type ConceptMapGroupModel struct {
+	Source   types.String             `tfsdk:"source"`
+	Target   types.String             `tfsdk:"target"`
+	Elements []ConceptMapElementModel `tfsdk:"elements"`
+}
+
+// ConceptMapElementModel describes the mappings of a source code.
+type ConceptMapElementModel struct {
+	Code    types.String            `tfsdk:"code"`
+	Display types.String            `tfsdk:"display"`
+	Targets []ConceptMapTargetModel `tfsdk:"targets"`
+}
+
+// ConceptMapTargetModel describes a target code.
+type ConceptMapTargetModel struct {
+	Code        types.String `tfsdk:"code"`
+	Display     types.String `tfsdk:"display"`
+	Equivalence types.String `tfsdk:"equivalence"`
+}
+
+func (r *ConceptMapResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_concept_map"
+}
+
+func (r *ConceptMapResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a FHIR `ConceptMap` of an Aidbox instance. Mappings are given inline or in a CSV file",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ConceptMap resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				Optional: true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Publication status. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(publicationStatuses...),
+				},
+			},
+			"source_value_set": schema.StringAttribute{
+				MarkdownDescription: "URL of the value set of the source codes",
+				Optional:            true,
+			},
+			"target_value_set": schema.StringAttribute{
+				MarkdownDescription: "URL of the value set of the target codes",
+				Optional:            true,
+			},
+			"groups": schema.ListNestedAttribute{
+				MarkdownDescription: "Mappings, grouped by source and target code system. Conflicts with `mappings_csv`",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"source": schema.StringAttribute{
+							MarkdownDescription: "Source code system URL",
+							Required:            true,
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "Target code system URL",
+							Required:            true,
+						},
+						"elements": schema.ListNestedAttribute{
+							MarkdownDescription: "Source codes and their targets",
+							Required:            true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"code": schema.StringAttribute{
+										Required: true,
+									},
+									"display": schema.StringAttribute{
+										Optional: true,
+									},
+									"targets": schema.ListNestedAttribute{
+										Required: true,
+										NestedObject: schema.NestedAttributeObject{
+											Attributes: map[string]schema.Attribute{
+												"code": schema.StringAttribute{
+													Required: true,
+												},
+												"display": schema.StringAttribute{
+													Optional: true,
+												},
+												"equivalence": schema.StringAttribute{
+													MarkdownDescription: "One of `" + strings.Join(conceptMapEquivalences, "`, `") + "`",
+													Required:            true,
+													Validators: []validator.String{
+														stringvalidator.OneOf(conceptMapEquivalences...),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"mappings_csv": schema.StringAttribute{
+				MarkdownDescription: "Path to a CSV file with a header row and the columns `source_system`, `source_code`, `source_display` (optional), " +
+					"`target_system`, `target_code`, `target_display` (optional) and `equivalence` (optional, defaults to `equivalent`). Conflicts with `groups`",
+				Optional: true,
+			},
+			"mappings_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of `mappings_csv`, used to detect changes to the file",
+				Computed:            true,
+			},
+			"mapping_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of source to target mappings stored on the instance",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ConceptMapResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("groups"),
+			path.MatchRoot("mappings_csv"),
+		),
+	}
+}
+
+func (r *ConceptMapResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+// ModifyPlan plans the number of mappings and the checksum of the CSV file,
+// so that changed mappings are uploaded again.
+func (r *ConceptMapResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ConceptMapResourceModel
+	if diags := req.Plan.Get(ctx, &plan); diags.HasError() || plan.MappingsCSV.IsUnknown() {
+		// Unknown groups cannot be counted yet.
+		return
+	}
+
+	checksum := types.StringNull()
+	groups := plan.Groups
+	if !plan.MappingsCSV.IsNull() {
+		var err error
+		groups, err = readConceptMapCSV(plan.MappingsCSV.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("mappings_csv"), "Invalid Mappings File", err.Error())
+			return
+		}
+		sum, err := fileSHA256(plan.MappingsCSV.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("mappings_csv"), "Invalid Mappings File", err.Error())
+			return
+		}
+		checksum = types.StringValue(sum)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("mappings_sha256"), checksum)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("mapping_count"), conceptMapMappingCount(groups))...)
+}
+
+func (r *ConceptMapResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model ConceptMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ConceptMapResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model ConceptMapResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cm aidboxclient.ConceptMap
+	err := r.client.GetResource(ctx, "fhir/ConceptMap", model.ID.ValueString(), &cm)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "ConceptMap not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch ConceptMap", fmt.Sprintf("Unable to fetch ConceptMap %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	model.URL = types.StringValue(cm.URL)
+	model.Name = optionalStringValue(cm.Name)
+	model.Status = types.StringValue(cm.Status)
+	model.SourceValueSet = optionalStringValue(cm.SourceCanonical)
+	model.TargetValueSet = optionalStringValue(cm.TargetCanonical)
+
+	groups := conceptMapGroupsToModel(cm.Group)
+	model.MappingCount = conceptMapMappingCount(groups)
+	// Mappings loaded from a file are compared through mapping_count and
+	// mappings_sha256 only.
+	if model.MappingsCSV.IsNull() {
+		model.Groups = groups
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ConceptMapResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model ConceptMapResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ConceptMapResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model ConceptMapResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "fhir/ConceptMap", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete ConceptMap",
+			fmt.Sprintf("Error while trying to delete the ConceptMap with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *ConceptMapResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *ConceptMapResource) save(ctx context.Context, model *ConceptMapResourceModel, diags *diag.Diagnostics) {
+	groups := model.Groups
+	attribute := path.Root("groups")
+	if !model.MappingsCSV.IsNull() {
+		attribute = path.Root("mappings_csv")
+
+		var err error
+		groups, err = readConceptMapCSV(model.MappingsCSV.ValueString())
+		if err != nil {
+			diags.AddAttributeError(attribute, "Invalid Mappings File", err.Error())
+			return
+		}
+	}
+
+	cm := aidboxclient.ConceptMap{
+		ResourceType:    "ConceptMap",
+		ID:              model.ID.ValueString(),
+		URL:             model.URL.ValueString(),
+		Name:            model.Name.ValueString(),
+		Status:          model.Status.ValueString(),
+		SourceCanonical: model.SourceValueSet.ValueString(),
+		TargetCanonical: model.TargetValueSet.ValueString(),
+		Group:           conceptMapGroupsFromModel(groups),
+	}
+
+	if err := r.client.PutResource(ctx, "fhir/ConceptMap", cm.ID, cm, nil); err != nil {
+		diags.Append(operationOutcomeDiagnostics(err, attribute, "Failed to Save ConceptMap")...)
+		return
+	}
+
+	model.MappingCount = conceptMapMappingCount(groups)
+}
+
+func conceptMapGroupsFromModel(groups []ConceptMapGroupModel) []aidboxclient.ConceptMapGroup {
+	result := make([]aidboxclient.ConceptMapGroup, 0, len(groups))
+	for _, g := range groups {
+		group := aidboxclient.ConceptMapGroup{Source: g.Source.ValueString(), Target: g.Target.ValueString()}
+		for _, e := range g.Elements {
+			element := aidboxclient.ConceptMapElement{Code: e.Code.ValueString(), Display: e.Display.ValueString()}
+			for _, t := range e.Targets {
+				element.Target = append(element.Target, aidboxclient.ConceptMapTarget{
+					Code:        t.Code.ValueString(),
+					Display:     t.Display.ValueString(),
+					Equivalence: t.Equivalence.ValueString(),
+				})
+			}
+			group.Element = append(group.Element, element)
+		}
+		result = append(result, group)
+	}
+	return result
+}
+
+func conceptMapGroupsToModel(groups []aidboxclient.ConceptMapGroup) []ConceptMapGroupModel {
+	var result []ConceptMapGroupModel
+	for _, g := range groups {
+		group := ConceptMapGroupModel{Source: types.StringValue(g.Source), Target: types.StringValue(g.Target)}
+		for _, e := range g.Element {
+			element := ConceptMapElementModel{Code: types.StringValue(e.Code), Display: optionalStringValue(e.Display)}
+			for _, t := range e.Target {
+				element.Targets = append(element.Targets, ConceptMapTargetModel{
+					Code:        types.StringValue(t.Code),
+					Display:     optionalStringValue(t.Display),
+					Equivalence: types.StringValue(t.Equivalence),
+				})
+			}
+			group.Elements = append(group.Elements, element)
+		}
+		result = append(result, group)
+	}
+	return result
+}
+
+// conceptMapMappingCount counts the source to target mappings of groups.
+func conceptMapMappingCount(groups []ConceptMapGroupModel) types.Int64 {
+	var count int64
+	for _, g := range groups {
+		for _, e := range g.Elements {
+			count += int64(len(e.Targets))
+		}
+	}
+	return types.Int64Value(count)
+}
+
+// readConceptMapCSV reads mappings from a CSV file, one source to target
+// mapping per row, and groups them by code system and source code.
+func readConceptMapCSV(name string) ([]ConceptMapGroupModel, error) {
+	records, err := readCSVRecords(name, "source_system", "source_code", "target_system", "target_code")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	var groups []ConceptMapGroupModel
+	groupIndex := map[[2]string]int{}
+	elementIndex := map[[3]string]int{}
+	for i, record := range records {
+		equivalence := record["equivalence"]
+		if equivalence == "" {
+			equivalence = "equivalent"
+		}
+		if !containsString(conceptMapEquivalences, equivalence) {
+			return nil, fmt.Errorf("%s: record %d: invalid equivalence %q", name, i+1, equivalence)
+		}
+
+		groupKey := [2]string{record["source_system"], record["target_system"]}
+		g, ok := groupIndex[groupKey]
+		if !ok {
+			g = len(groups)
+			groupIndex[groupKey] = g
+			groups = append(groups, ConceptMapGroupModel{
+				Source: types.StringValue(record["source_system"]),
+				Target: types.StringValue(record["target_system"]),
+			})
+		}
+
+		elementKey := [3]string{groupKey[0], groupKey[1], record["source_code"]}
+		e, ok := elementIndex[elementKey]
+		if !ok {
+			e = len(groups[g].Elements)
+			elementIndex[elementKey] = e
+			groups[g].Elements = append(groups[g].Elements, ConceptMapElementModel{
+				Code:    types.StringValue(record["source_code"]),
+				Display: optionalStringValue(record["source_display"]),
+			})
+		}
+
+		groups[g].Elements[e].Targets = append(groups[g].Elements[e].Targets, ConceptMapTargetModel{
+			Code:        types.StringValue(record["target_code"]),
+			Display:     optionalStringValue(record["target_display"]),
+			Equivalence: types.StringValue(equivalence),
+		})
+	}
+	return groups, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// readCSVRecords reads a CSV file whose first row names the columns. Each
+// record maps column names to values; the required columns must be present
+// and non-empty.
+func readCSVRecords(name string, required ...string) ([]map[string]string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return parseCSVRecords(f, required...)
+}
+
+func parseCSVRecords(r io.Reader, required ...string) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+	}
+
+	for _, column := range required {
+		found := false
+		for _, h := range header {
+			found = found || h == column
+		}
+		if !found {
+			return nil, fmt.Errorf("missing required column %q", column)
+		}
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(row) {
+				record[column] = strings.TrimSpace(row[i])
+			}
+		}
+		for _, column := range required {
+			if record[column] == "" {
+				return nil, fmt.Errorf("line %d: empty %q", line, column)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCSVRecords(t *testing.T) {
+	records, err := parseCSVRecords(strings.NewReader("\ufeffCode, Display\nA1, First\nA2\n"), "code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0]["code"] != "A1" || records[0]["display"] != "First" || records[1]["display"] != "" {
+		t.Errorf("unexpected records %v", records)
+	}
+
+	errorCases := map[string]string{
+		"":                   "missing header row",
+		"display\nFirst\n":   `missing required column "code"`,
+		"code,display\n,x\n": `line 2: empty "code"`,
+	}
+	for input, expected := range errorCases {
+		_, err := parseCSVRecords(strings.NewReader(input), "code")
+		if err == nil || err.Error() != expected {
+			t.Errorf("%q: expected error %q, got %v", input, expected, err)
+		}
+	}
+}
+
+func TestReadConceptMapCSV(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "mappings.csv")
+	content := "source_system,source_code,target_system,target_code,equivalence\n" +
+		"http://a,1,http://b,x,\n" +
+		"http://a,1,http://b,y,wider\n" +
+		"http://a,2,http://c,z,narrower\n"
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := readConceptMapCSV(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 || len(groups[0].Elements) != 1 || len(groups[0].Elements[0].Targets) != 2 {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+	if eq := groups[0].Elements[0].Targets[0].Equivalence.ValueString(); eq != "equivalent" {
+		t.Errorf("expected the default equivalence, got %q", eq)
+	}
+	if count := conceptMapMappingCount(groups).ValueInt64(); count != 3 {
+		t.Errorf("expected 3 mappings, got %d", count)
+	}
+
+	if err := os.WriteFile(name, []byte("source_system,source_code,target_system,target_code,equivalence\nhttp://a,1,http://b,x,same\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readConceptMapCSV(name); err == nil {
+		t.Error("expected an invalid equivalence to be rejected")
+	}
+}
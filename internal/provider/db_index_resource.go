@@ -0,0 +1,286 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DBIndexResource{}
+var _ resource.ResourceWithImportState = &DBIndexResource{}
+
+var sqlIdentifierRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func NewDBIndexResource() resource.Resource {
+	return &DBIndexResource{}
+}
+
+// DBIndexResource defines the resource implementation.
+type DBIndexResource struct {
+	client Client
+}
+
+// DBIndexResourceModel describes the resource data model.
+type DBIndexResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Schema       types.String `tfsdk:"schema"`
+	Table        types.String `tfsdk:"table"`
+	Method       types.String `tfsdk:"method"`
+	Expression   types.String `tfsdk:"expression"`
+	Unique       types.Bool   `tfsdk:"unique"`
+	Where        types.String `tfsdk:"where"`
+	Concurrently types.Bool   `tfsdk:"concurrently"`
+	Definition   types.String `tfsdk:"definition"`
+}
+
+func (r *DBIndexResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_db_index"
+}
+
+func (r *DBIndexResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	identifierValidators := []validator.String{
+		stringvalidator.RegexMatches(sqlIdentifierRegexp, "must be a lowercase Postgres identifier"),
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a Postgres index of an Aidbox instance through the `/$sql` endpoint",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "`<schema>.<name>`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Index name",
+				Required:            true,
+				Validators:          identifierValidators,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				MarkdownDescription: "Database schema. Defaults to `public`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("public"),
+				Validators:          identifierValidators,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"table": schema.StringAttribute{
+				MarkdownDescription: "Resource table, e.g. `patient`",
+				Required:            true,
+				Validators:          identifierValidators,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"method": schema.StringAttribute{
+				MarkdownDescription: "Index method: `btree`, `gin`, `gist`, `hash` or `brin`. Defaults to `btree`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("btree"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("btree", "gin", "gist", "hash", "brin"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"expression": schema.StringAttribute{
+				MarkdownDescription: "Indexed columns or expressions, without the surrounding parentheses, e.g. `(resource#>>'{name,0,family}')` or `resource jsonb_path_ops`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"unique": schema.BoolAttribute{
+				MarkdownDescription: "Create a unique index. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"where": schema.StringAttribute{
+				MarkdownDescription: "Predicate of a partial index",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"concurrently": schema.BoolAttribute{
+				MarkdownDescription: "Build and drop the index with `CONCURRENTLY` to avoid locking the table. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"definition": schema.StringAttribute{
+				MarkdownDescription: "Index definition as reported by `pg_indexes`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *DBIndexResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *DBIndexResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model DBIndexResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	index := aidboxclient.DBIndex{
+		Schema:       model.Schema.ValueString(),
+		Name:         model.Name.ValueString(),
+		Table:        model.Table.ValueString(),
+		Method:       model.Method.ValueString(),
+		Expression:   model.Expression.ValueString(),
+		Unique:       model.Unique.ValueBool(),
+		Where:        model.Where.ValueString(),
+		Concurrently: model.Concurrently.ValueBool(),
+	}
+
+	tflog.Debug(ctx, "Creating index", map[string]interface{}{"statement": index.CreateStatement()})
+	if err := r.client.CreateDBIndex(ctx, index); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Create Index",
+			fmt.Sprintf("Unable to create index %s: %s\n\nIf an index with this name already exists, import it or drop it first. "+
+				"If the index was built CONCURRENTLY, an INVALID index may be left behind and must be dropped manually.", index.Name, err),
+		)
+		return
+	}
+
+	state, err := r.client.GetDBIndex(ctx, index.Schema, index.Name)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Read Index", fmt.Sprintf("Unable to read index %s: %s", index.Name, err))
+		return
+	}
+	if state == nil {
+		resp.Diagnostics.AddError("Index Not Found", fmt.Sprintf("Index %s was not found in pg_indexes after creation.", index.Name))
+		return
+	}
+
+	model.ID = types.StringValue(index.Schema + "." + index.Name)
+	model.Definition = types.StringValue(state.Definition)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *DBIndexResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model DBIndexResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state, err := r.client.GetDBIndex(ctx, model.Schema.ValueString(), model.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Read Index", fmt.Sprintf("Unable to read index %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	if state == nil {
+		tflog.Warn(ctx, "Index not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Table, method and uniqueness drift force a replacement.
+	model.Table = types.StringValue(state.Table)
+	model.Unique = types.BoolValue(state.Unique)
+	if state.Method != "" {
+		model.Method = types.StringValue(state.Method)
+	}
+	model.Definition = types.StringValue(state.Definition)
+
+	// Postgres rewrites the expression and predicate, so the configured text
+	// is kept unless it differs once normalized. After an import, they are
+	// only known to Postgres.
+	imported := model.Expression.IsNull()
+	if imported || indexExpressionDrifted(model.Expression, state.Expression) {
+		model.Expression = types.StringValue(state.Expression)
+	}
+	if imported || indexExpressionDrifted(model.Where, state.Where) {
+		model.Where = optionalStringValue(state.Where)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *DBIndexResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model DBIndexResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only "concurrently" can change in place and it does not affect the index.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *DBIndexResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model DBIndexResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DropDBIndex(ctx, model.Schema.ValueString(), model.Name.ValueString(), model.Concurrently.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Drop Index",
+			fmt.Sprintf("Error while trying to drop the index %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *DBIndexResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import IDs are "<schema>.<name>" or "<name>" for the public schema.
+	schemaName, name, ok := strings.Cut(req.ID, ".")
+	if !ok {
+		schemaName, name = "public", req.ID
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), schemaName+"."+name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("schema"), schemaName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("concurrently"), true)...)
+}
+
+// indexExpressionDrifted reports whether an expression reported by Postgres
+// differs from the one in state.
+func indexExpressionDrifted(prior types.String, actual string) bool {
+	return aidboxclient.NormalizeIndexExpression(prior.ValueString()) != aidboxclient.NormalizeIndexExpression(actual)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DBIndexSuggestionsDataSource{}
+
+func NewDBIndexSuggestionsDataSource() datasource.DataSource {
+	return &DBIndexSuggestionsDataSource{}
+}
+
+// DBIndexSuggestionsDataSource defines the data source implementation.
+type DBIndexSuggestionsDataSource struct {
+	client Client
+}
+
+// DBIndexSuggestionsDataSourceModel describes the data source data model.
+type DBIndexSuggestionsDataSourceModel struct {
+	ResourceType types.String             `tfsdk:"resource_type"`
+	SearchParam  types.String             `tfsdk:"search_param"`
+	Suggestions  []DBIndexSuggestionModel `tfsdk:"suggestions"`
+}
+
+// DBIndexSuggestionModel describes a single suggested index.
+type DBIndexSuggestionModel struct {
+	Name types.String `tfsdk:"name"`
+	SQL  types.String `tfsdk:"sql"`
+}
+
+func (d *DBIndexSuggestionsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_db_index_suggestions"
+}
+
+func (d *DBIndexSuggestionsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Indexes suggested by Aidbox to speed up searches by a search parameter",
+		Attributes: map[string]schema.Attribute{
+			"resource_type": schema.StringAttribute{
+				MarkdownDescription: "Resource type, e.g. `Observation`",
+				Required:            true,
+			},
+			"search_param": schema.StringAttribute{
+				MarkdownDescription: "Search parameter name, e.g. `date`",
+				Required:            true,
+			},
+			"suggestions": schema.ListNestedAttribute{
+				MarkdownDescription: "Suggested indexes",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Index name",
+							Computed:            true,
+						},
+						"sql": schema.StringAttribute{
+							MarkdownDescription: "`CREATE INDEX` statement",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *DBIndexSuggestionsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *DBIndexSuggestionsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model DBIndexSuggestionsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	suggestions, err := d.client.SuggestIndex(ctx, model.ResourceType.ValueString(), model.SearchParam.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Fetch Index Suggestions",
+			fmt.Sprintf("Unable to fetch index suggestions for %s?%s: %s", model.ResourceType.ValueString(), model.SearchParam.ValueString(), err),
+		)
+		return
+	}
+
+	model.Suggestions = make([]DBIndexSuggestionModel, 0, len(suggestions))
+	for _, s := range suggestions {
+		model.Suggestions = append(model.Suggestions, DBIndexSuggestionModel{
+			Name: types.StringValue(s.IndexName),
+			SQL:  types.StringValue(s.SQL),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
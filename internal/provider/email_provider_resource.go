@@ -0,0 +1,368 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &EmailProviderResource{}
+var _ resource.ResourceWithConfigValidators = &EmailProviderResource{}
+var _ resource.ResourceWithImportState = &EmailProviderResource{}
+
+func NewEmailProviderResource() resource.Resource {
+	return &EmailProviderResource{}
+}
+
+// EmailProviderResource defines the resource implementation.
+type EmailProviderResource struct {
+	client Client
+}
+
+// EmailProviderResourceModel describes the resource data model.
+type EmailProviderResourceModel struct {
+	ID       types.String        `tfsdk:"id"`
+	Name     types.String        `tfsdk:"name"`
+	From     types.String        `tfsdk:"from"`
+	SMTP     *EmailSMTPModel     `tfsdk:"smtp"`
+	Mailgun  *EmailMailgunModel  `tfsdk:"mailgun"`
+	Sendgrid *EmailSendgridModel `tfsdk:"sendgrid"`
+}
+
+// EmailSMTPModel describes an SMTP server.
+type EmailSMTPModel struct {
+	Host              types.String `tfsdk:"host"`
+	Port              types.Int64  `tfsdk:"port"`
+	TLS               types.Bool   `tfsdk:"tls"`
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWOVersion types.Int64  `tfsdk:"password_wo_version"`
+}
+
+// EmailMailgunModel describes a Mailgun account.
+type EmailMailgunModel struct {
+	URL             types.String `tfsdk:"url"`
+	Username        types.String `tfsdk:"username"`
+	APIKey          types.String `tfsdk:"api_key"`
+	APIKeyWOVersion types.Int64  `tfsdk:"api_key_wo_version"`
+}
+
+// EmailSendgridModel describes a SendGrid account.
+type EmailSendgridModel struct {
+	APIKey          types.String `tfsdk:"api_key"`
+	APIKeyWOVersion types.Int64  `tfsdk:"api_key_wo_version"`
+}
+
+func (r *EmailProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_email_provider"
+}
+
+func (r *EmailProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an email provider of the `provider` AidboxConfig, used to send notifications. " +
+			"Aidbox sends mail with the provider named `default`. Exactly one of `smtp`, `mailgun` and `sendgrid` must be set. " +
+			"Passwords and API keys are write-only arguments, which require Terraform 1.11 or later: they are neither stored in state nor read back. " +
+			"Change the matching `*_wo_version` to send a new secret, as changes to a write-only argument alone are not detected",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Same as `name`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Provider name. Defaults to `default`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("default"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"from": schema.StringAttribute{
+				MarkdownDescription: "Sender address",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(emailRegexp, "must be an email address"),
+				},
+			},
+			"smtp": schema.SingleNestedAttribute{
+				MarkdownDescription: "SMTP server",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"host": schema.StringAttribute{
+						Required: true,
+					},
+					"port": schema.Int64Attribute{
+						Required: true,
+						Validators: []validator.Int64{
+							int64validator.Between(1, 65535),
+						},
+					},
+					"tls": schema.BoolAttribute{
+						MarkdownDescription: "Whether to connect with TLS",
+						Optional:            true,
+					},
+					"username": schema.StringAttribute{
+						Optional: true,
+					},
+					"password": schema.StringAttribute{
+						MarkdownDescription: "Write-only",
+						Optional:            true,
+						Sensitive:           true,
+						WriteOnly:           true,
+					},
+					"password_wo_version": schema.Int64Attribute{
+						MarkdownDescription: "Change to send a new `password`",
+						Optional:            true,
+						Validators: []validator.Int64{
+							int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("password")),
+						},
+					},
+				},
+			},
+			"mailgun": schema.SingleNestedAttribute{
+				MarkdownDescription: "Mailgun account",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						MarkdownDescription: "Messages API URL of the domain, e.g. `https://api.mailgun.net/v3/mg.example.com/messages`",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+						},
+					},
+					"username": schema.StringAttribute{
+						MarkdownDescription: "Defaults to `api` on the Mailgun side",
+						Optional:            true,
+					},
+					"api_key": schema.StringAttribute{
+						MarkdownDescription: "Write-only",
+						Required:            true,
+						Sensitive:           true,
+						WriteOnly:           true,
+					},
+					"api_key_wo_version": schema.Int64Attribute{
+						MarkdownDescription: "Change to send a new `api_key`",
+						Optional:            true,
+					},
+				},
+			},
+			"sendgrid": schema.SingleNestedAttribute{
+				MarkdownDescription: "SendGrid account",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"api_key": schema.StringAttribute{
+						MarkdownDescription: "Write-only",
+						Required:            true,
+						Sensitive:           true,
+						WriteOnly:           true,
+					},
+					"api_key_wo_version": schema.Int64Attribute{
+						MarkdownDescription: "Change to send a new `api_key`",
+						Optional:            true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *EmailProviderResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("smtp"),
+			path.MatchRoot("mailgun"),
+			path.MatchRoot("sendgrid"),
+		),
+	}
+}
+
+func (r *EmailProviderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *EmailProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model, config EmailProviderResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *EmailProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model EmailProviderResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	provider, err := r.client.GetEmailProvider(ctx, model.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Email Provider", fmt.Sprintf("Unable to fetch email provider %s: %s", model.Name.ValueString(), err))
+		return
+	}
+	if provider == nil {
+		tflog.Warn(ctx, "Email provider not found, removing from state", map[string]interface{}{"name": model.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	mapEmailProviderToModel(&model, *provider)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *EmailProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model, config EmailProviderResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, config, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *EmailProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model EmailProviderResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteEmailProvider(ctx, model.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Email Provider",
+			fmt.Sprintf("Error while trying to delete the email provider %s: %s", model.Name.ValueString(), err),
+		)
+	}
+}
+
+// ImportState imports a provider by name. Secrets cannot be read back; the
+// next apply sets them again when a `*_wo_version` is configured.
+func (r *EmailProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
+
+// save saves the provider of the plan. Secrets are write-only, so they are
+// taken from the configuration.
+func (r *EmailProviderResource) save(ctx context.Context, model *EmailProviderResourceModel, config EmailProviderResourceModel, diags *diag.Diagnostics) {
+	name := model.Name.ValueString()
+	if err := r.client.PutEmailProvider(ctx, name, emailProviderFromModel(*model, config)); err != nil {
+		diags.AddError("Failed to Save Email Provider", fmt.Sprintf("Unable to save email provider %s: %s", name, err))
+		return
+	}
+	model.ID = types.StringValue(name)
+}
+
+func emailProviderFromModel(model, config EmailProviderResourceModel) aidboxclient.EmailProvider {
+	provider := aidboxclient.EmailProvider{From: model.From.ValueString()}
+	switch {
+	case model.SMTP != nil:
+		provider.Type = "smtp"
+		provider.Host = model.SMTP.Host.ValueString()
+		provider.Port = model.SMTP.Port.ValueInt64()
+		provider.TLS = model.SMTP.TLS.ValueBoolPointer()
+		provider.Username = model.SMTP.Username.ValueString()
+		if config.SMTP != nil {
+			provider.Password = config.SMTP.Password.ValueString()
+		}
+	case model.Mailgun != nil:
+		provider.Type = "mailgun"
+		provider.URL = model.Mailgun.URL.ValueString()
+		provider.Username = model.Mailgun.Username.ValueString()
+		if config.Mailgun != nil {
+			provider.APIKey = config.Mailgun.APIKey.ValueString()
+		}
+	case model.Sendgrid != nil:
+		provider.Type = "sendgrid"
+		if config.Sendgrid != nil {
+			provider.APIKey = config.Sendgrid.APIKey.ValueString()
+		}
+	}
+	return provider
+}
+
+// mapEmailProviderToModel sets the non-secret fields of the model. Secrets
+// are write-only and never stored; their versions are kept from the prior
+// state.
+func mapEmailProviderToModel(model *EmailProviderResourceModel, provider aidboxclient.EmailProvider) {
+	model.ID = model.Name
+	model.From = types.StringValue(provider.From)
+
+	var version types.Int64
+	switch {
+	case model.SMTP != nil:
+		version = model.SMTP.PasswordWOVersion
+	case model.Mailgun != nil:
+		version = model.Mailgun.APIKeyWOVersion
+	case model.Sendgrid != nil:
+		version = model.Sendgrid.APIKeyWOVersion
+	}
+	if version.IsUnknown() {
+		version = types.Int64Null()
+	}
+	model.SMTP, model.Mailgun, model.Sendgrid = nil, nil, nil
+
+	switch provider.Type {
+	case "smtp":
+		model.SMTP = &EmailSMTPModel{
+			Host:              types.StringValue(provider.Host),
+			Port:              types.Int64Value(provider.Port),
+			TLS:               types.BoolPointerValue(provider.TLS),
+			Username:          optionalStringValue(provider.Username),
+			Password:          types.StringNull(),
+			PasswordWOVersion: version,
+		}
+	case "mailgun":
+		model.Mailgun = &EmailMailgunModel{
+			URL:             types.StringValue(provider.URL),
+			Username:        optionalStringValue(provider.Username),
+			APIKey:          types.StringNull(),
+			APIKeyWOVersion: version,
+		}
+	case "sendgrid":
+		model.Sendgrid = &EmailSendgridModel{
+			APIKey:          types.StringNull(),
+			APIKeyWOVersion: version,
+		}
+	}
+}
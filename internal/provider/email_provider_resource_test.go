@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestEmailProviderWriteOnlySecrets(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	r := &EmailProviderResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	schema := schemaResp.Schema
+	if diags := schema.ValidateImplementation(ctx); diags.HasError() {
+		t.Fatal(diags)
+	}
+	objectType := schema.Type().TerraformType(ctx).(tftypes.Object)
+	sendgridType := objectType.AttributeTypes["sendgrid"].(tftypes.Object)
+
+	// Write-only values are only present in the configuration; the plan has
+	// them as null.
+	value := func(apiKey interface{}, version int64) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for name, attrType := range objectType.AttributeTypes {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+		values["name"] = tftypes.NewValue(tftypes.String, "default")
+		values["from"] = tftypes.NewValue(tftypes.String, "noreply@example.com")
+		values["sendgrid"] = tftypes.NewValue(sendgridType, map[string]tftypes.Value{
+			"api_key":            tftypes.NewValue(tftypes.String, apiKey),
+			"api_key_wo_version": tftypes.NewValue(tftypes.Number, version),
+		})
+		return tftypes.NewValue(objectType, values)
+	}
+
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, resource.CreateRequest{
+		Config: tfsdk.Config{Schema: schema, Raw: value("SG.secret", 1)},
+		Plan:   tfsdk.Plan{Schema: schema, Raw: value(nil, 1)},
+	}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	if got := client.emailProviders["default"]; got.Type != "sendgrid" || got.APIKey != "SG.secret" {
+		t.Errorf("expected the configured API key to be sent, got %+v", got)
+	}
+
+	readResp := resource.ReadResponse{State: resp.State}
+	r.Read(ctx, resource.ReadRequest{State: resp.State}, &readResp)
+	if readResp.Diagnostics.HasError() {
+		t.Fatal(readResp.Diagnostics)
+	}
+
+	var model EmailProviderResourceModel
+	if diags := readResp.State.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.Sendgrid == nil {
+		t.Fatal("expected the sendgrid account in state")
+	}
+	if !model.Sendgrid.APIKey.IsNull() {
+		t.Errorf("expected no API key in state, got %s", model.Sendgrid.APIKey)
+	}
+	if model.Sendgrid.APIKeyWOVersion.ValueInt64() != 1 {
+		t.Errorf("expected the API key version to be kept, got %s", model.Sendgrid.APIKeyWOVersion)
+	}
+}
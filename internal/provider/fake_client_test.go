@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"terraform-provider-aidbox/internal/aidboxclient"
+)
+
+// fakeClient implements Client for unit tests. Resources and email
+// providers are kept in memory; the other methods are stubbed per test and
+// panic when called without a stub.
+type fakeClient struct {
+	Client
+
+	resources      map[string]json.RawMessage
+	emailProviders map[string]aidboxclient.EmailProvider
+
+	importNDJSON func(ctx context.Context, content io.Reader, chunkSize int) (aidboxclient.BulkImportResult, error)
+	parseHl7v2   func(ctx context.Context, message string, strict bool) (aidboxclient.Hl7v2ParseResult, error)
+	applyMapping func(ctx context.Context, id string, input json.RawMessage) (json.RawMessage, error)
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		resources:      map[string]json.RawMessage{},
+		emailProviders: map[string]aidboxclient.EmailProvider{},
+	}
+}
+
+func (c *fakeClient) GetResource(ctx context.Context, collection, id string, out interface{}) error {
+	raw, ok := c.resources[collection+"/"+id]
+	if !ok {
+		return &aidboxclient.APIError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func (c *fakeClient) PutResource(ctx context.Context, collection, id string, in, out interface{}) error {
+	raw, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	c.resources[collection+"/"+id] = raw
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func (c *fakeClient) DeleteResource(ctx context.Context, collection, id string) error {
+	delete(c.resources, collection+"/"+id)
+	return nil
+}
+
+func (c *fakeClient) GetEmailProvider(ctx context.Context, name string) (*aidboxclient.EmailProvider, error) {
+	provider, ok := c.emailProviders[name]
+	if !ok {
+		return nil, nil
+	}
+	return &provider, nil
+}
+
+func (c *fakeClient) PutEmailProvider(ctx context.Context, name string, provider aidboxclient.EmailProvider) error {
+	c.emailProviders[name] = provider
+	return nil
+}
+
+func (c *fakeClient) ImportNDJSON(ctx context.Context, content io.Reader, chunkSize int) (aidboxclient.BulkImportResult, error) {
+	return c.importNDJSON(ctx, content, chunkSize)
+}
+
+func (c *fakeClient) ParseHl7v2(ctx context.Context, message string, strict bool) (aidboxclient.Hl7v2ParseResult, error) {
+	return c.parseHl7v2(ctx, message, strict)
+}
+
+func (c *fakeClient) ApplyMapping(ctx context.Context, id string, input json.RawMessage) (json.RawMessage, error) {
+	return c.applyMapping(ctx, id, input)
+}
@@ -0,0 +1,309 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &FHIRPackageResource{}
+var _ resource.ResourceWithModifyPlan = &FHIRPackageResource{}
+var _ resource.ResourceWithImportState = &FHIRPackageResource{}
+
+const (
+	fhirPackagePollInterval    = 2 * time.Second
+	fhirPackageMaxPollInterval = 30 * time.Second
+	fhirPackageInstallTimeout  = 30 * time.Minute
+)
+
+var fhirPackageNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+func NewFHIRPackageResource() resource.Resource {
+	return &FHIRPackageResource{}
+}
+
+// FHIRPackageResource defines the resource implementation.
+type FHIRPackageResource struct {
+	client Client
+}
+
+// FHIRPackageResourceModel describes the resource data model.
+type FHIRPackageResourceModel struct {
+	ID           types.String   `tfsdk:"id"`
+	Name         types.String   `tfsdk:"name"`
+	Version      types.String   `tfsdk:"version"`
+	SourceFile   types.String   `tfsdk:"source_file"`
+	SourceSHA256 types.String   `tfsdk:"source_sha256"`
+	Timeouts     timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *FHIRPackageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_fhir_package"
+}
+
+func (r *FHIRPackageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Installs a FHIR NPM package (implementation guide) into an Aidbox instance, either from the package " +
+			"registry or from a local `.tgz` archive. Changing the version or the archive upgrades the package in place",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Package name",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Package name, e.g. `hl7.fhir.us.core`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(fhirPackageNameRegexp, "must be a lowercase NPM package name"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Package version, e.g. `6.1.0`. When `source_file` is set, it must match the version of the archive",
+				Required:            true,
+			},
+			"source_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a local package archive (`.tgz`) to upload instead of installing from the registry",
+				Optional:            true,
+			},
+			"source_sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of `source_file`, used to detect changes to the archive",
+				Computed:            true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				CreateDescription: "How long to wait for the installation. Defaults to `30m`",
+				UpdateDescription: "How long to wait for the upgrade. Defaults to `30m`",
+			}),
+		},
+	}
+}
+
+func (r *FHIRPackageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+// ModifyPlan computes the checksum of the package archive, so that a
+// changed archive plans an upgrade.
+func (r *FHIRPackageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var sourceFile types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("source_file"), &sourceFile)...)
+	if resp.Diagnostics.HasError() || sourceFile.IsUnknown() {
+		return
+	}
+
+	checksum := types.StringNull()
+	if !sourceFile.IsNull() {
+		sum, err := fileSHA256(sourceFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source_file"), "Failed to Read Package Archive", err.Error())
+			return
+		}
+		checksum = types.StringValue(sum)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("source_sha256"), checksum)...)
+}
+
+func (r *FHIRPackageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model FHIRPackageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Create(ctx, fhirPackageInstallTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.install(ctx, model, timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	model.ID = model.Name
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *FHIRPackageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model FHIRPackageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	pkg, err := r.client.GetFHIRPackage(ctx, model.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch FHIR Package", fmt.Sprintf("Unable to fetch FHIR package %s: %s", model.Name.ValueString(), err))
+		return
+	}
+	if pkg == nil {
+		tflog.Warn(ctx, "FHIR package not installed, removing from state", map[string]interface{}{"name": model.Name.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	model.Version = types.StringValue(pkg.Version)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *FHIRPackageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model FHIRPackageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Update(ctx, fhirPackageInstallTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.install(ctx, model, timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *FHIRPackageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model FHIRPackageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.UninstallFHIRPackage(ctx, model.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Uninstall FHIR Package",
+			fmt.Sprintf("Error while trying to uninstall the FHIR package %s: %s", model.Name.ValueString(), err),
+		)
+	}
+}
+
+func (r *FHIRPackageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), req.ID)...)
+}
+
+// install installs or upgrades the package, waits for the installation to
+// complete and checks that the expected version is installed.
+func (r *FHIRPackageResource) install(ctx context.Context, model FHIRPackageResourceModel, timeout time.Duration, diags *diag.Diagnostics) {
+	name, version := model.Name.ValueString(), model.Version.ValueString()
+
+	var statusURL string
+	var err error
+	if model.SourceFile.IsNull() {
+		tflog.Info(ctx, "Installing FHIR package", map[string]interface{}{"package": name + "@" + version})
+		statusURL, err = r.client.InstallFHIRPackage(ctx, name+"@"+version)
+	} else {
+		statusURL, err = r.upload(ctx, model.SourceFile.ValueString())
+	}
+	if err != nil {
+		diags.AddError("Failed to Install FHIR Package", fmt.Sprintf("Unable to install FHIR package %s@%s: %s", name, version, err))
+		return
+	}
+
+	if statusURL != "" {
+		_, err := r.client.WaitAsync(ctx, statusURL, aidboxclient.PollOptions{
+			Description: "FHIR package installation",
+			Interval:    fhirPackagePollInterval,
+			Backoff:     1.5,
+			MaxInterval: fhirPackageMaxPollInterval,
+			Timeout:     timeout,
+		})
+		if err != nil {
+			diags.AddError("Failed to Install FHIR Package", fmt.Sprintf("Installation of FHIR package %s@%s failed: %s", name, version, err))
+			return
+		}
+	}
+
+	pkg, err := r.client.GetFHIRPackage(ctx, name)
+	if err != nil {
+		diags.AddError("Failed to Fetch FHIR Package", fmt.Sprintf("Unable to fetch FHIR package %s: %s", name, err))
+		return
+	}
+	if pkg == nil || pkg.Version != version {
+		installed := "no version"
+		if pkg != nil {
+			installed = "version " + pkg.Version
+		}
+		diags.AddAttributeError(
+			path.Root("version"),
+			"Unexpected FHIR Package Version",
+			fmt.Sprintf("Expected FHIR package %s@%s to be installed, but the instance reports %s. "+
+				"When installing from source_file, version must match the version of the archive.", name, version, installed),
+		)
+	}
+}
+
+func (r *FHIRPackageResource) upload(ctx context.Context, sourceFile string) (string, error) {
+	f, err := os.Open(sourceFile)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	tflog.Info(ctx, "Uploading FHIR package", map[string]interface{}{"file": sourceFile})
+	return r.client.UploadFHIRPackage(ctx, filepath.Base(sourceFile), f)
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 checksum of a file.
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
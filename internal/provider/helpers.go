@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// optionalStringValue maps empty API values to null so that unset optional
+// attributes do not produce a diff.
+func optionalStringValue(s string) types.String {
+	if s == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(s)
+}
+
+// preserveSecret returns the secret read from Aidbox, or the prior value when
+// Aidbox omits secrets from responses.
+func preserveSecret(prior types.String, remote string) types.String {
+	if remote == "" {
+		return prior
+	}
+	return types.StringValue(remote)
+}
+
+// stringListValue maps a list of strings read from Aidbox to a list
+// attribute, which is null when the list is absent.
+func stringListValue(values []string) types.List {
+	if values == nil {
+		return types.ListNull(types.StringType)
+	}
+	elements := make([]attr.Value, 0, len(values))
+	for _, v := range values {
+		elements = append(elements, types.StringValue(v))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// stringMapValue maps strings read from Aidbox to a map attribute, which is
+// null when the map is absent.
+func stringMapValue(values map[string]string) types.Map {
+	if values == nil {
+		return types.MapNull(types.StringType)
+	}
+	elements := make(map[string]attr.Value, len(values))
+	for k, v := range values {
+		elements[k] = types.StringValue(v)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
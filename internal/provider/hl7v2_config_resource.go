@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &Hl7v2ConfigResource{}
+var _ resource.ResourceWithImportState = &Hl7v2ConfigResource{}
+
+func NewHl7v2ConfigResource() resource.Resource {
+	return &Hl7v2ConfigResource{}
+}
+
+// Hl7v2ConfigResource defines the resource implementation.
+type Hl7v2ConfigResource struct {
+	client Client
+}
+
+// Hl7v2ConfigResourceModel describes the resource data model.
+type Hl7v2ConfigResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	IsStrict  types.Bool   `tfsdk:"is_strict"`
+	MappingID types.String `tfsdk:"mapping_id"`
+}
+
+func (r *Hl7v2ConfigResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hl7v2_config"
+}
+
+func (r *Hl7v2ConfigResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an `Hl7v2Config`, which controls how HL7v2 messages posted as `Hl7v2Message` resources are parsed " +
+			"and which `Mapping` turns them into FHIR resources",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Hl7v2Config resource ID, referenced by messages as `config`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"is_strict": schema.BoolAttribute{
+				MarkdownDescription: "Reject messages that do not follow the structure of their message type. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"mapping_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the `Mapping` applied to parsed messages",
+				Required:            true,
+			},
+		},
+	}
+}
+
+func (r *Hl7v2ConfigResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *Hl7v2ConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model Hl7v2ConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "Hl7v2Config", model.ID.ValueString(), hl7v2ConfigFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save Hl7v2Config", fmt.Sprintf("Unable to save Hl7v2Config %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Hl7v2ConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model Hl7v2ConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config aidboxclient.Hl7v2Config
+	err := r.client.GetResource(ctx, "Hl7v2Config", model.ID.ValueString(), &config)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Hl7v2Config not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Hl7v2Config", fmt.Sprintf("Unable to fetch Hl7v2Config %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapHl7v2ConfigToModel(&model, config)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Hl7v2ConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model Hl7v2ConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "Hl7v2Config", model.ID.ValueString(), hl7v2ConfigFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save Hl7v2Config", fmt.Sprintf("Unable to save Hl7v2Config %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *Hl7v2ConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model Hl7v2ConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "Hl7v2Config", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Hl7v2Config",
+			fmt.Sprintf("Error while trying to delete the Hl7v2Config with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *Hl7v2ConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func mapHl7v2ConfigToModel(model *Hl7v2ConfigResourceModel, config aidboxclient.Hl7v2Config) {
+	model.IsStrict = types.BoolValue(config.IsStrict)
+	model.MappingID = types.StringNull()
+	if config.Mapping != nil {
+		model.MappingID = types.StringValue(config.Mapping.ID)
+	}
+}
+
+func hl7v2ConfigFromModel(model Hl7v2ConfigResourceModel) aidboxclient.Hl7v2Config {
+	return aidboxclient.Hl7v2Config{
+		ResourceType: "Hl7v2Config",
+		ID:           model.ID.ValueString(),
+		IsStrict:     model.IsStrict.ValueBool(),
+		Mapping: &aidboxclient.Reference{
+			ID:           model.MappingID.ValueString(),
+			ResourceType: "Mapping",
+		},
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHl7v2ConfigFromModel(t *testing.T) {
+	model := Hl7v2ConfigResourceModel{
+		ID:        types.StringValue("adt"),
+		IsStrict:  types.BoolValue(true),
+		MappingID: types.StringValue("adt-to-fhir"),
+	}
+
+	config := hl7v2ConfigFromModel(model)
+	if config.ResourceType != "Hl7v2Config" || config.ID != "adt" || !config.IsStrict {
+		t.Errorf("unexpected config %+v", config)
+	}
+	if config.Mapping == nil || config.Mapping.ResourceType != "Mapping" || config.Mapping.ID != "adt-to-fhir" {
+		t.Errorf("unexpected mapping reference %+v", config.Mapping)
+	}
+
+	read := Hl7v2ConfigResourceModel{ID: model.ID}
+	mapHl7v2ConfigToModel(&read, config)
+	if read != model {
+		t.Errorf("expected %+v after a round trip, got %+v", model, read)
+	}
+}
+
+func TestMapHl7v2ConfigToModelWithoutMapping(t *testing.T) {
+	model := Hl7v2ConfigResourceModel{
+		ID:        types.StringValue("adt"),
+		IsStrict:  types.BoolValue(true),
+		MappingID: types.StringValue("adt-to-fhir"),
+	}
+
+	mapHl7v2ConfigToModel(&model, aidboxclient.Hl7v2Config{ResourceType: "Hl7v2Config", ID: "adt"})
+	if model.IsStrict.ValueBool() {
+		t.Error("expected is_strict to be refreshed")
+	}
+	if !model.MappingID.IsNull() {
+		t.Errorf("expected a removed mapping to be null, got %s", model.MappingID)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &Hl7v2ParseDataSource{}
+var _ datasource.DataSourceWithConfigValidators = &Hl7v2ParseDataSource{}
+
+func NewHl7v2ParseDataSource() datasource.DataSource {
+	return &Hl7v2ParseDataSource{}
+}
+
+// Hl7v2ParseDataSource defines the data source implementation.
+type Hl7v2ParseDataSource struct {
+	client Client
+}
+
+// Hl7v2ParseDataSourceModel describes the data source data model.
+type Hl7v2ParseDataSourceModel struct {
+	Message     types.String   `tfsdk:"message"`
+	Strict      types.Bool     `tfsdk:"strict"`
+	MappingID   types.String   `tfsdk:"mapping_id"`
+	MappingBody types.String   `tfsdk:"mapping_body"`
+	Parsed      types.String   `tfsdk:"parsed"`
+	Errors      []types.String `tfsdk:"errors"`
+	Result      types.String   `tfsdk:"result"`
+}
+
+func (d *Hl7v2ParseDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hl7v2_parse"
+}
+
+func (d *Hl7v2ParseDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Parses a sample HL7v2 message on the instance and optionally runs a mapping on it, without storing anything. " +
+			"Use it to verify a mapping at plan time: the plan fails when the message cannot be parsed in strict mode or the mapping fails",
+		Attributes: map[string]schema.Attribute{
+			"message": schema.StringAttribute{
+				MarkdownDescription: "HL7v2 message. Segments may be separated by any line ending",
+				Required:            true,
+			},
+			"strict": schema.BoolAttribute{
+				MarkdownDescription: "Fail on messages that do not follow the structure of their message type. " +
+					"Otherwise parse errors are reported as warnings. Defaults to `false`",
+				Optional: true,
+			},
+			"mapping_id": schema.StringAttribute{
+				MarkdownDescription: "ID of a saved `Mapping` to run on the parsed message",
+				Optional:            true,
+			},
+			"mapping_body": schema.StringAttribute{
+				MarkdownDescription: "JUTE template to run on the parsed message, as a JSON document. " +
+					"Usually the `body` of an `aidbox_mapping`, so that a change is verified before it is applied",
+				Optional: true,
+				Validators: []validator.String{
+					jsonStringValidator{},
+				},
+			},
+			"parsed": schema.StringAttribute{
+				MarkdownDescription: "Parsed message as a JSON document",
+				Computed:            true,
+			},
+			"errors": schema.ListAttribute{
+				MarkdownDescription: "Parse errors",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"result": schema.StringAttribute{
+				MarkdownDescription: "Output of the mapping as a JSON document, usually a transaction `Bundle`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *Hl7v2ParseDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.Conflicting(
+			path.MatchRoot("mapping_id"),
+			path.MatchRoot("mapping_body"),
+		),
+	}
+}
+
+func (d *Hl7v2ParseDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *Hl7v2ParseDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model Hl7v2ParseDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	message := model.Message.ValueString()
+	result, err := d.client.ParseHl7v2(ctx, message, model.Strict.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("message"), "Failed to Parse HL7v2 Message", err.Error())
+		return
+	}
+
+	errors := result.ErrorMessages()
+	model.Errors = make([]types.String, 0, len(errors))
+	for _, e := range errors {
+		model.Errors = append(model.Errors, types.StringValue(e))
+	}
+	resp.Diagnostics.Append(hl7v2ParseDiagnostics(errors, model.Strict.ValueBool())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	model.Parsed = jsonStringValue(types.StringNull(), result.Parsed)
+
+	model.Result = types.StringNull()
+	if !model.MappingID.IsNull() || !model.MappingBody.IsNull() {
+		if model.Parsed.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("message"), "Invalid HL7v2 Message", "The message could not be parsed, so the mapping was not run.")
+			return
+		}
+
+		// Mappings run on the Hl7v2Message the instance would store.
+		input, err := json.Marshal(map[string]interface{}{
+			"resourceType": "Hl7v2Message",
+			"src":          message,
+			"parsed":       json.RawMessage(result.Parsed),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to Run Mapping", err.Error())
+			return
+		}
+
+		var output json.RawMessage
+		if !model.MappingID.IsNull() {
+			output, err = d.client.ApplyMapping(ctx, model.MappingID.ValueString(), input)
+		} else {
+			output, err = d.client.DebugMapping(ctx, json.RawMessage(model.MappingBody.ValueString()), input)
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Failed to Run Mapping", fmt.Sprintf("The mapping failed on the message: %s", err))
+			return
+		}
+		model.Result = jsonStringValue(types.StringNull(), output)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// hl7v2ParseDiagnostics reports parse errors as an error in strict mode and
+// as a warning otherwise.
+func hl7v2ParseDiagnostics(errors []string, strict bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if len(errors) == 0 {
+		return diags
+	}
+
+	detail := fmt.Sprintf("The message has %d parse errors:\n\n%s", len(errors), strings.Join(errors, "\n"))
+	if strict {
+		diags.AddAttributeError(path.Root("message"), "Invalid HL7v2 Message", detail)
+	} else {
+		diags.AddAttributeWarning(path.Root("message"), "Invalid HL7v2 Message", detail)
+	}
+	return diags
+}
@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestHl7v2ParseRead(t *testing.T) {
+	ctx := context.Background()
+	d := &Hl7v2ParseDataSource{}
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	parseErrors := []string{"Segment PV1 is not expected", "Field PID.3 is required"}
+
+	testCases := map[string]struct {
+		errors   []string
+		strict   bool
+		errs     int
+		warnings int
+	}{
+		"no errors strict": {
+			strict: true,
+		},
+		"no errors": {},
+		"strict": {
+			errors: parseErrors,
+			strict: true,
+			errs:   1,
+		},
+		"non-strict": {
+			errors:   parseErrors,
+			warnings: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			client := newFakeClient()
+			client.parseHl7v2 = func(ctx context.Context, message string, strict bool) (aidboxclient.Hl7v2ParseResult, error) {
+				if strict != tc.strict {
+					t.Errorf("expected strict %t, got %t", tc.strict, strict)
+				}
+				errors, _ := json.Marshal(tc.errors)
+				return aidboxclient.Hl7v2ParseResult{
+					Parsed: json.RawMessage(`{"type": "ADT_A01"}`),
+					Errors: errors,
+				}, nil
+			}
+			d.client = client
+
+			values := map[string]tftypes.Value{}
+			for name, attrType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+			values["message"] = tftypes.NewValue(tftypes.String, "MSH|^~\\&|A|B|C|D|20240101||ADT^A01|1|P|2.5")
+			values["strict"] = tftypes.NewValue(tftypes.Bool, tc.strict)
+
+			req := datasource.ReadRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+			}
+			resp := datasource.ReadResponse{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+			}
+			d.Read(ctx, req, &resp)
+
+			if got := len(resp.Diagnostics.Errors()); got != tc.errs {
+				t.Fatalf("expected %d errors, got %d: %v", tc.errs, got, resp.Diagnostics)
+			}
+			if got := len(resp.Diagnostics.Warnings()); got != tc.warnings {
+				t.Errorf("expected %d warnings, got %d: %v", tc.warnings, got, resp.Diagnostics)
+			}
+			for _, diag := range resp.Diagnostics {
+				for _, e := range tc.errors {
+					if !strings.Contains(diag.Detail(), e) {
+						t.Errorf("expected %q in %q", e, diag.Detail())
+					}
+				}
+			}
+
+			if tc.errs > 0 {
+				if !resp.State.Raw.IsNull() {
+					t.Error("expected no state on a parse error")
+				}
+				return
+			}
+
+			var model Hl7v2ParseDataSourceModel
+			resp.Diagnostics.Append(resp.State.Get(ctx, &model)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+			if model.Parsed.IsNull() {
+				t.Error("expected the parsed message in state")
+			}
+			if len(model.Errors) != len(tc.errors) {
+				t.Errorf("expected %d errors in state, got %d", len(tc.errors), len(model.Errors))
+			}
+			if !model.Result.IsNull() {
+				t.Errorf("expected no mapping result, got %s", model.Result)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &HostedInstanceResource{}
+var _ resource.ResourceWithImportState = &HostedInstanceResource{}
+
+const (
+	hostedInstancePollInterval    = 10 * time.Second
+	hostedInstanceMaxPollInterval = time.Minute
+	hostedInstanceCreateTimeout   = 20 * time.Minute
+	hostedInstanceDeleteTimeout   = 10 * time.Minute
+)
+
+func NewHostedInstanceResource() resource.Resource {
+	return &HostedInstanceResource{}
+}
+
+// HostedInstanceResource defines the resource implementation.
+type HostedInstanceResource struct {
+	client Client
+}
+
+// HostedInstanceResourceModel describes the resource data model.
+type HostedInstanceResourceModel struct {
+	ID                types.String   `tfsdk:"id"`
+	LicenseID         types.String   `tfsdk:"license_id"`
+	Name              types.String   `tfsdk:"name"`
+	Region            types.String   `tfsdk:"region"`
+	Status            types.String   `tfsdk:"status"`
+	URL               types.String   `tfsdk:"url"`
+	AdminClientID     types.String   `tfsdk:"admin_client_id"`
+	AdminClientSecret types.String   `tfsdk:"admin_client_secret"`
+	Timeouts          timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *HostedInstanceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosted_instance"
+}
+
+func (r *HostedInstanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Launches an Aidbox instance hosted by the portal for a license and waits until it is ready. " +
+			"`url`, `admin_client_id` and `admin_client_secret` can configure a provider alias that manages the instance",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Hosted instance ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"license_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the license the instance runs with, e.g. `aidbox_license.sandbox.id`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Instance name, also used as the subdomain of its URL",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(boxIDRegexp, "must start with a lowercase letter and contain only lowercase letters, digits and dashes"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "Hosting region. Defaults to the portal default",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Provisioning status, `ready` once the instance accepts requests",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "URL of the instance",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin_client_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the admin client of the instance",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"admin_client_secret": schema.StringAttribute{
+				MarkdownDescription: "Secret of the admin client of the instance",
+				Computed:            true,
+				Sensitive:           true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				Delete:            true,
+				CreateDescription: "How long to wait for the instance to become ready. Defaults to `20m`",
+				DeleteDescription: "How long to wait for the instance to be deleted. Defaults to `10m`",
+			}),
+		},
+	}
+}
+
+func (r *HostedInstanceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := portalProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *HostedInstanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model HostedInstanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Create(ctx, hostedInstanceCreateTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err := r.client.CreateHostedInstance(ctx, model.LicenseID.ValueString(), model.Name.ValueString(), model.Region.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Create Hosted Instance", fmt.Sprintf("Unable to create hosted instance %s: %s", model.Name.ValueString(), err))
+		return
+	}
+	mapHostedInstanceToModel(&model, instance)
+
+	// Save the instance before waiting, so that it is tainted rather than
+	// leaked when provisioning fails.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err = r.waitReady(ctx, instance.ID, timeout)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Create Hosted Instance", fmt.Sprintf("Hosted instance %s did not become ready: %s", model.Name.ValueString(), err))
+		return
+	}
+	mapHostedInstanceToModel(&model, instance)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *HostedInstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model HostedInstanceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instance, err := r.client.GetHostedInstance(ctx, model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Hosted Instance", fmt.Sprintf("Unable to fetch hosted instance %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	if instance == nil {
+		tflog.Warn(ctx, "Hosted instance not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	mapHostedInstanceToModel(&model, *instance)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *HostedInstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// All configurable attributes require replacement.
+	var model HostedInstanceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *HostedInstanceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model HostedInstanceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Delete(ctx, hostedInstanceDeleteTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteHostedInstance(ctx, model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Hosted Instance",
+			fmt.Sprintf("Error while trying to delete the hosted instance with ID %s: %s", model.ID.ValueString(), err),
+		)
+		return
+	}
+
+	if err := r.waitDeleted(ctx, model.ID.ValueString(), timeout); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Hosted Instance",
+			fmt.Sprintf("Hosted instance %s was not deleted: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *HostedInstanceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// waitReady polls the instance until it is ready or failed.
+func (r *HostedInstanceResource) waitReady(ctx context.Context, id string, timeout time.Duration) (aidboxclient.HostedInstance, error) {
+	var instance aidboxclient.HostedInstance
+	err := aidboxclient.Poll(ctx, hostedInstancePollOptions("hosted instance "+id, timeout), func(ctx context.Context) (bool, string, error) {
+		current, err := r.client.GetHostedInstance(ctx, id)
+		if err != nil {
+			return false, "", err
+		}
+		if current == nil {
+			return false, "", fmt.Errorf("instance %s disappeared while provisioning", id)
+		}
+		instance = *current
+		switch instance.Status {
+		case aidboxclient.HostedInstanceReady:
+			return true, "", nil
+		case aidboxclient.HostedInstanceFailed:
+			return false, "", fmt.Errorf("provisioning failed: %s", instance.Error)
+		}
+		return false, instance.Status, nil
+	})
+	return instance, err
+}
+
+// waitDeleted polls the instance until it no longer exists.
+func (r *HostedInstanceResource) waitDeleted(ctx context.Context, id string, timeout time.Duration) error {
+	return aidboxclient.Poll(ctx, hostedInstancePollOptions("deletion of hosted instance "+id, timeout), func(ctx context.Context) (bool, string, error) {
+		instance, err := r.client.GetHostedInstance(ctx, id)
+		if err != nil {
+			return false, "", err
+		}
+		if instance == nil {
+			return true, "", nil
+		}
+		return false, instance.Status, nil
+	})
+}
+
+func hostedInstancePollOptions(description string, timeout time.Duration) aidboxclient.PollOptions {
+	return aidboxclient.PollOptions{
+		Description: description,
+		Interval:    hostedInstancePollInterval,
+		Backoff:     1.5,
+		MaxInterval: hostedInstanceMaxPollInterval,
+		Timeout:     timeout,
+	}
+}
+
+func mapHostedInstanceToModel(model *HostedInstanceResourceModel, instance aidboxclient.HostedInstance) {
+	model.ID = types.StringValue(instance.ID)
+	model.Status = types.StringValue(instance.Status)
+	if instance.Name != "" {
+		model.Name = types.StringValue(instance.Name)
+	}
+	if instance.License != "" {
+		model.LicenseID = types.StringValue(instance.License)
+	}
+	if instance.Region != "" || model.Region.IsUnknown() {
+		model.Region = optionalStringValue(instance.Region)
+	}
+	model.URL = optionalStringValue(instance.URL)
+	model.AdminClientID = optionalStringValue(instance.AdminClientID)
+	// The portal only returns the secret while the instance is provisioned.
+	if instance.AdminClientSecret != "" || model.AdminClientSecret.IsUnknown() {
+		model.AdminClientSecret = optionalStringValue(instance.AdminClientSecret)
+	}
+}
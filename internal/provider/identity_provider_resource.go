@@ -0,0 +1,357 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &IdentityProviderResource{}
+var _ resource.ResourceWithImportState = &IdentityProviderResource{}
+
+// attributePathRegexp matches dot-separated attribute paths such as
+// "name.givenName".
+var attributePathRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*(\.[A-Za-z_][A-Za-z0-9_-]*)*$`)
+
+func NewIdentityProviderResource() resource.Resource {
+	return &IdentityProviderResource{}
+}
+
+// IdentityProviderResource defines the resource implementation.
+type IdentityProviderResource struct {
+	client Client
+}
+
+// IdentityProviderResourceModel describes the resource data model.
+type IdentityProviderResourceModel struct {
+	ID                types.String                 `tfsdk:"id"`
+	Type              types.String                 `tfsdk:"type"`
+	Title             types.String                 `tfsdk:"title"`
+	Active            types.Bool                   `tfsdk:"active"`
+	AuthorizeEndpoint types.String                 `tfsdk:"authorize_endpoint"`
+	TokenEndpoint     types.String                 `tfsdk:"token_endpoint"`
+	UserinfoEndpoint  types.String                 `tfsdk:"userinfo_endpoint"`
+	UserinfoSource    types.String                 `tfsdk:"userinfo_source"`
+	Scopes            types.List                   `tfsdk:"scopes"`
+	Client            *IdentityProviderClientModel `tfsdk:"client"`
+	UserinfoMapping   types.Map                    `tfsdk:"userinfo_mapping"`
+}
+
+// IdentityProviderClientModel describes the client registered at the
+// identity provider.
+type IdentityProviderClientModel struct {
+	ID          types.String `tfsdk:"id"`
+	Secret      types.String `tfsdk:"secret"`
+	RedirectURI types.String `tfsdk:"redirect_uri"`
+}
+
+func (r *IdentityProviderResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity_provider"
+}
+
+func (r *IdentityProviderResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	urlValidators := []validator.String{
+		stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an Aidbox `IdentityProvider`, an external OpenID Connect or OAuth 2.0 provider such as Keycloak or Okta users can log in with",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "IdentityProvider resource ID, also used in the login URL",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Provider type, e.g. `OIDC`, `okta` or `keycloak`. Defaults to `OIDC`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("OIDC"),
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "Name shown on the login page",
+				Optional:            true,
+			},
+			"active": schema.BoolAttribute{
+				MarkdownDescription: "Whether users can log in with the provider. Defaults to `true`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+			},
+			"authorize_endpoint": schema.StringAttribute{
+				MarkdownDescription: "OAuth 2.0 authorization endpoint",
+				Required:            true,
+				Validators:          urlValidators,
+			},
+			"token_endpoint": schema.StringAttribute{
+				MarkdownDescription: "OAuth 2.0 token endpoint",
+				Required:            true,
+				Validators:          urlValidators,
+			},
+			"userinfo_endpoint": schema.StringAttribute{
+				MarkdownDescription: "OpenID Connect userinfo endpoint",
+				Optional:            true,
+				Validators:          urlValidators,
+			},
+			"userinfo_source": schema.StringAttribute{
+				MarkdownDescription: "Where user claims are read from: `id-token` or `userinfo-endpoint`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("id-token", "userinfo-endpoint"),
+				},
+			},
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "Requested scopes, e.g. `[\"openid\", \"profile\", \"email\"]`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"client": schema.SingleNestedAttribute{
+				MarkdownDescription: "Client Aidbox is registered as at the provider",
+				Required:            true,
+				Attributes: map[string]schema.Attribute{
+					"id": schema.StringAttribute{
+						MarkdownDescription: "Client ID",
+						Required:            true,
+					},
+					"secret": schema.StringAttribute{
+						MarkdownDescription: "Client secret",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"redirect_uri": schema.StringAttribute{
+						MarkdownDescription: "Redirect URI registered at the provider",
+						Optional:            true,
+						Validators:          urlValidators,
+					},
+				},
+			},
+			"userinfo_mapping": schema.MapAttribute{
+				MarkdownDescription: "Maps `User` attributes to user claims, both as dot-separated paths, e.g. `{ \"name.givenName\" = \"given_name\" }`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(attributePathRegexp, "must be a dot-separated attribute path")),
+					mapvalidator.ValueStringsAre(stringvalidator.RegexMatches(attributePathRegexp, "must be a dot-separated claim path")),
+				},
+			},
+		},
+	}
+}
+
+func (r *IdentityProviderResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *IdentityProviderResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model IdentityProviderResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *IdentityProviderResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model IdentityProviderResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var idp aidboxclient.IdentityProvider
+	err := r.client.GetResource(ctx, "IdentityProvider", model.ID.ValueString(), &idp)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "IdentityProvider not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch IdentityProvider", fmt.Sprintf("Unable to fetch IdentityProvider %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapIdentityProviderToModel(&model, idp)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *IdentityProviderResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model IdentityProviderResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *IdentityProviderResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model IdentityProviderResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "IdentityProvider", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete IdentityProvider",
+			fmt.Sprintf("Error while trying to delete the IdentityProvider with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *IdentityProviderResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *IdentityProviderResource) save(ctx context.Context, model IdentityProviderResourceModel, diags *diag.Diagnostics) {
+	var scopes []string
+	var mapping map[string]string
+	diags.Append(model.Scopes.ElementsAs(ctx, &scopes, false)...)
+	diags.Append(model.UserinfoMapping.ElementsAs(ctx, &mapping, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	idp := aidboxclient.IdentityProvider{
+		ResourceType:      "IdentityProvider",
+		ID:                model.ID.ValueString(),
+		Type:              model.Type.ValueString(),
+		Title:             model.Title.ValueString(),
+		Active:            model.Active.ValueBool(),
+		AuthorizeEndpoint: model.AuthorizeEndpoint.ValueString(),
+		TokenEndpoint:     model.TokenEndpoint.ValueString(),
+		UserinfoEndpoint:  model.UserinfoEndpoint.ValueString(),
+		UserinfoSource:    model.UserinfoSource.ValueString(),
+		Client: aidboxclient.IdentityProviderClient{
+			ID:          model.Client.ID.ValueString(),
+			Secret:      model.Client.Secret.ValueString(),
+			RedirectURI: model.Client.RedirectURI.ValueString(),
+		},
+		Scopes: scopes,
+		ToScim: userinfoMappingToScim(mapping),
+	}
+
+	if err := r.client.PutResource(ctx, "IdentityProvider", idp.ID, idp, nil); err != nil {
+		diags.AddError("Failed to Save IdentityProvider", fmt.Sprintf("Unable to save IdentityProvider %s: %s", idp.ID, err))
+	}
+}
+
+func mapIdentityProviderToModel(model *IdentityProviderResourceModel, idp aidboxclient.IdentityProvider) {
+	model.Type = types.StringValue(idp.Type)
+	model.Title = optionalStringValue(idp.Title)
+	model.Active = types.BoolValue(idp.Active)
+	model.AuthorizeEndpoint = types.StringValue(idp.AuthorizeEndpoint)
+	model.TokenEndpoint = types.StringValue(idp.TokenEndpoint)
+	model.UserinfoEndpoint = optionalStringValue(idp.UserinfoEndpoint)
+	model.UserinfoSource = optionalStringValue(idp.UserinfoSource)
+
+	model.Scopes = types.ListNull(types.StringType)
+	if len(idp.Scopes) > 0 {
+		model.Scopes = stringListValue(idp.Scopes)
+	}
+
+	secret := types.StringNull()
+	if model.Client != nil {
+		secret = model.Client.Secret
+	}
+	model.Client = &IdentityProviderClientModel{
+		ID:          types.StringValue(idp.Client.ID),
+		Secret:      preserveSecret(secret, idp.Client.Secret),
+		RedirectURI: optionalStringValue(idp.Client.RedirectURI),
+	}
+
+	model.UserinfoMapping = stringMapValue(userinfoMappingFromScim(idp.ToScim))
+}
+
+// userinfoMappingToScim converts dot-separated attribute and claim paths to
+// the nested toScim mapping, e.g. {"name.givenName": "given_name"} to
+// {"name": {"givenName": ["given_name"]}}.
+func userinfoMappingToScim(mapping map[string]string) map[string]interface{} {
+	if len(mapping) == 0 {
+		return nil
+	}
+
+	result := map[string]interface{}{}
+	for attribute, claim := range mapping {
+		keys := strings.Split(attribute, ".")
+		node := result
+		for _, key := range keys[:len(keys)-1] {
+			child, ok := node[key].(map[string]interface{})
+			if !ok {
+				child = map[string]interface{}{}
+				node[key] = child
+			}
+			node = child
+		}
+		node[keys[len(keys)-1]] = strings.Split(claim, ".")
+	}
+	return result
+}
+
+// userinfoMappingFromScim is the inverse of userinfoMappingToScim.
+func userinfoMappingFromScim(toScim map[string]interface{}) map[string]string {
+	if len(toScim) == 0 {
+		return nil
+	}
+
+	result := map[string]string{}
+	var walk func(prefix string, node map[string]interface{})
+	walk = func(prefix string, node map[string]interface{}) {
+		for key, value := range node {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				walk(prefix+key+".", v)
+			case []interface{}:
+				claim := make([]string, 0, len(v))
+				for _, part := range v {
+					claim = append(claim, fmt.Sprint(part))
+				}
+				result[prefix+key] = strings.Join(claim, ".")
+			case []string:
+				result[prefix+key] = strings.Join(v, ".")
+			case string:
+				result[prefix+key] = v
+			}
+		}
+	}
+	walk("", toScim)
+	return result
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestUserinfoMapping(t *testing.T) {
+	mapping := map[string]string{
+		"userName":        "preferred_username",
+		"name.givenName":  "given_name",
+		"name.familyName": "family_name",
+		"address.country": "address.country",
+	}
+
+	toScim := userinfoMappingToScim(mapping)
+	data, err := json.Marshal(toScim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"address":{"country":["address","country"]},"name":{"familyName":["family_name"],"givenName":["given_name"]},"userName":["preferred_username"]}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+
+	// Mappings are read back from JSON responses.
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if back := userinfoMappingFromScim(decoded); !reflect.DeepEqual(back, mapping) {
+		t.Errorf("expected %v, got %v", mapping, back)
+	}
+
+	if userinfoMappingToScim(nil) != nil || userinfoMappingFromScim(nil) != nil {
+		t.Error("expected empty mappings to be omitted")
+	}
+}
+
+func TestIdentityProviderModelUnknownCollections(t *testing.T) {
+	ctx := context.Background()
+	r := &IdentityProviderResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "okta")
+	values["scopes"] = tftypes.NewValue(objectType.AttributeTypes["scopes"], tftypes.UnknownValue)
+	values["userinfo_mapping"] = tftypes.NewValue(objectType.AttributeTypes["userinfo_mapping"], tftypes.UnknownValue)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+
+	var model IdentityProviderResourceModel
+	if diags := plan.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("expected unknown collections to decode, got %v", diags)
+	}
+	if !model.Scopes.IsUnknown() || !model.UserinfoMapping.IsUnknown() {
+		t.Errorf("expected unknown collections, got %s and %s", model.Scopes, model.UserinfoMapping)
+	}
+}
+
+func TestMapIdentityProviderToModel(t *testing.T) {
+	ctx := context.Background()
+	var model IdentityProviderResourceModel
+	mapIdentityProviderToModel(&model, aidboxclient.IdentityProvider{
+		Type:   "okta",
+		Scopes: []string{"openid", "profile"},
+		ToScim: map[string]interface{}{"name": map[string]interface{}{"givenName": []interface{}{"given_name"}}},
+	})
+
+	var scopes []string
+	var mapping map[string]string
+	model.Scopes.ElementsAs(ctx, &scopes, false)
+	model.UserinfoMapping.ElementsAs(ctx, &mapping, false)
+	if !reflect.DeepEqual(scopes, []string{"openid", "profile"}) {
+		t.Errorf("unexpected scopes %v", scopes)
+	}
+	if !reflect.DeepEqual(mapping, map[string]string{"name.givenName": "given_name"}) {
+		t.Errorf("unexpected userinfo mapping %v", mapping)
+	}
+
+	mapIdentityProviderToModel(&model, aidboxclient.IdentityProvider{Type: "okta"})
+	if !model.Scopes.Equal(types.ListNull(types.StringType)) || !model.UserinfoMapping.Equal(types.MapNull(types.StringType)) {
+		t.Errorf("expected absent collections to be null, got %s and %s", model.Scopes, model.UserinfoMapping)
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &JobResource{}
+var _ resource.ResourceWithConfigValidators = &JobResource{}
+var _ resource.ResourceWithValidateConfig = &JobResource{}
+var _ resource.ResourceWithImportState = &JobResource{}
+
+// jobTimeRegexp matches daily run times, e.g. "02:30".
+var jobTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func NewJobResource() resource.Resource {
+	return &JobResource{}
+}
+
+// JobResource defines the resource implementation.
+type JobResource struct {
+	client Client
+}
+
+// JobResourceModel describes the resource data model.
+type JobResourceModel struct {
+	ID         types.String  `tfsdk:"id"`
+	Every      types.String  `tfsdk:"every"`
+	At         types.String  `tfsdk:"at"`
+	SQL        types.String  `tfsdk:"sql"`
+	RPC        *JobRPCModel  `tfsdk:"rpc"`
+	HTTP       *JobHTTPModel `tfsdk:"http"`
+	LastStatus types.String  `tfsdk:"last_status"`
+	LastRunAt  types.String  `tfsdk:"last_run_at"`
+	LastError  types.String  `tfsdk:"last_error"`
+	NextRunAt  types.String  `tfsdk:"next_run_at"`
+}
+
+// JobRPCModel describes an RPC call action.
+type JobRPCModel struct {
+	Method types.String `tfsdk:"method"`
+	Params types.String `tfsdk:"params"`
+}
+
+// JobHTTPModel describes an HTTP request action.
+type JobHTTPModel struct {
+	Method  types.String `tfsdk:"method"`
+	URL     types.String `tfsdk:"url"`
+	Headers types.Map    `tfsdk:"headers"`
+	Body    types.String `tfsdk:"body"`
+}
+
+func (r *JobResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_job"
+}
+
+func (r *JobResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an `AidboxJob`, a task the Aidbox scheduler runs at an interval or daily. " +
+			"Exactly one of `every` and `at`, and exactly one of `sql`, `rpc` and `http` must be set. " +
+			"Cron expressions are not supported, as the Aidbox scheduler only runs jobs at an interval or at a daily time. " +
+			"The status of the last run is read on refresh, and a failed last run is reported as a warning",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "AidboxJob resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"every": schema.StringAttribute{
+				MarkdownDescription: "Interval between runs as a duration in whole seconds, e.g. `15m` or `6h`",
+				Optional:            true,
+			},
+			"at": schema.StringAttribute{
+				MarkdownDescription: "Daily run time in UTC, as `HH:MM`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(jobTimeRegexp, "must be a time of day as HH:MM"),
+				},
+			},
+			"sql": schema.StringAttribute{
+				MarkdownDescription: "SQL statement to run",
+				Optional:            true,
+			},
+			"rpc": schema.SingleNestedAttribute{
+				MarkdownDescription: "RPC method of the instance to call",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"method": schema.StringAttribute{
+						MarkdownDescription: "RPC method, e.g. `aidbox.bulk/cleanup`",
+						Required:            true,
+					},
+					"params": schema.StringAttribute{
+						MarkdownDescription: "Parameters as a JSON object",
+						Optional:            true,
+						Validators: []validator.String{
+							jsonStringValidator{},
+						},
+					},
+				},
+			},
+			"http": schema.SingleNestedAttribute{
+				MarkdownDescription: "HTTP request to send",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"method": schema.StringAttribute{
+						MarkdownDescription: "HTTP method. Defaults to `POST`",
+						Optional:            true,
+						Computed:            true,
+						Default:             stringdefault.StaticString("POST"),
+						Validators: []validator.String{
+							stringvalidator.OneOf("GET", "POST", "PUT", "PATCH", "DELETE"),
+						},
+					},
+					"url": schema.StringAttribute{
+						Required: true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+						},
+					},
+					"headers": schema.MapAttribute{
+						MarkdownDescription: "HTTP headers, e.g. `Authorization`",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"body": schema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+			"last_status": schema.StringAttribute{
+				MarkdownDescription: "Status of the last run, e.g. `success` or `failed`. Null until the job first runs",
+				Computed:            true,
+			},
+			"last_run_at": schema.StringAttribute{
+				MarkdownDescription: "Start time of the last run",
+				Computed:            true,
+			},
+			"last_error": schema.StringAttribute{
+				MarkdownDescription: "Error of the last run, null when it succeeded",
+				Computed:            true,
+			},
+			"next_run_at": schema.StringAttribute{
+				MarkdownDescription: "Scheduled start time of the next run",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *JobResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("every"),
+			path.MatchRoot("at"),
+		),
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("sql"),
+			path.MatchRoot("rpc"),
+			path.MatchRoot("http"),
+		),
+	}
+}
+
+func (r *JobResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var every types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("every"), &every)...)
+	if resp.Diagnostics.HasError() || every.IsNull() || every.IsUnknown() {
+		return
+	}
+
+	if _, err := jobIntervalSeconds(every.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("every"), "Invalid Interval", err.Error())
+	}
+}
+
+func (r *JobResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *JobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model JobResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model JobResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var job aidboxclient.AidboxJob
+	err := r.client.GetResource(ctx, "AidboxJob", model.ID.ValueString(), &job)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "AidboxJob not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch AidboxJob", fmt.Sprintf("Unable to fetch AidboxJob %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	mapJobToModel(&model, job)
+
+	status, err := r.client.GetJobStatus(ctx, model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch AidboxJobStatus", fmt.Sprintf("Unable to fetch the status of AidboxJob %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	mapJobStatusToModel(&model, status)
+	if status != nil && status.ErrorMessage() != "" {
+		resp.Diagnostics.AddWarning(
+			"AidboxJob Failed",
+			fmt.Sprintf("The last run of AidboxJob %s, started at %s, failed: %s", model.ID.ValueString(), status.Start, status.ErrorMessage()),
+		)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *JobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model JobResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model JobResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "AidboxJob", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete AidboxJob",
+			fmt.Sprintf("Error while trying to delete the AidboxJob with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *JobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// save creates or replaces the job and reads the status of its last run.
+func (r *JobResource) save(ctx context.Context, model *JobResourceModel, diags *diag.Diagnostics) {
+	job, jobDiags := jobFromModel(ctx, *model)
+	diags.Append(jobDiags...)
+	if diags.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "AidboxJob", job.ID, job, nil); err != nil {
+		diags.AddError("Failed to Save AidboxJob", fmt.Sprintf("Unable to save AidboxJob %s: %s", job.ID, err))
+		return
+	}
+
+	status, err := r.client.GetJobStatus(ctx, job.ID)
+	if err != nil {
+		diags.AddError("Failed to Fetch AidboxJobStatus", fmt.Sprintf("Unable to fetch the status of AidboxJob %s: %s", job.ID, err))
+		return
+	}
+	mapJobStatusToModel(model, status)
+}
+
+// jobIntervalSeconds parses the interval of a job, which Aidbox stores in
+// seconds.
+func jobIntervalSeconds(every string) (int64, error) {
+	d, err := time.ParseDuration(every)
+	if err != nil {
+		return 0, fmt.Errorf("'every' must be a duration such as \"15m\" or \"6h\": %s", err)
+	}
+	if d < time.Second || d%time.Second != 0 {
+		return 0, fmt.Errorf("'every' must be a positive whole number of seconds, got %s", every)
+	}
+	return int64(d / time.Second), nil
+}
+
+func jobFromModel(ctx context.Context, model JobResourceModel) (aidboxclient.AidboxJob, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	job := aidboxclient.AidboxJob{
+		ResourceType: "AidboxJob",
+		ID:           model.ID.ValueString(),
+		Type:         "periodic",
+		At:           model.At.ValueString(),
+	}
+	if !model.Every.IsNull() {
+		seconds, err := jobIntervalSeconds(model.Every.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("every"), "Invalid Interval", err.Error())
+			return job, diags
+		}
+		job.Every = seconds
+	}
+
+	switch {
+	case !model.SQL.IsNull():
+		job.Action = aidboxclient.JobAction{Engine: aidboxclient.JobEngineSQL, Query: model.SQL.ValueString()}
+	case model.RPC != nil:
+		rpc := &aidboxclient.JobRPCAction{Method: model.RPC.Method.ValueString()}
+		if !model.RPC.Params.IsNull() {
+			rpc.Params = json.RawMessage(model.RPC.Params.ValueString())
+		}
+		job.Action = aidboxclient.JobAction{Engine: aidboxclient.JobEngineRPC, RPC: rpc}
+	case model.HTTP != nil:
+		var headers map[string]string
+		diags.Append(model.HTTP.Headers.ElementsAs(ctx, &headers, false)...)
+		job.Action = aidboxclient.JobAction{Engine: aidboxclient.JobEngineHTTP, HTTP: &aidboxclient.JobHTTPAction{
+			Method:  model.HTTP.Method.ValueString(),
+			URL:     model.HTTP.URL.ValueString(),
+			Headers: headers,
+			Body:    model.HTTP.Body.ValueString(),
+		}}
+	}
+	return job, diags
+}
+
+func mapJobToModel(model *JobResourceModel, job aidboxclient.AidboxJob) {
+	// Keep the configured interval when it is the same number of seconds,
+	// e.g. "1h" rather than "3600s".
+	if job.Every == 0 {
+		model.Every = types.StringNull()
+	} else if seconds, err := jobIntervalSeconds(model.Every.ValueString()); err != nil || seconds != job.Every {
+		model.Every = types.StringValue(fmt.Sprintf("%ds", job.Every))
+	}
+	model.At = optionalStringValue(job.At)
+
+	prior := model.RPC
+	model.SQL, model.RPC, model.HTTP = types.StringNull(), nil, nil
+
+	switch job.Action.Engine {
+	case aidboxclient.JobEngineSQL:
+		model.SQL = types.StringValue(job.Action.Query)
+	case aidboxclient.JobEngineRPC:
+		if job.Action.RPC != nil {
+			params := types.StringNull()
+			if prior != nil {
+				params = prior.Params
+			}
+			model.RPC = &JobRPCModel{
+				Method: types.StringValue(job.Action.RPC.Method),
+				Params: jsonStringValue(params, job.Action.RPC.Params),
+			}
+		}
+	case aidboxclient.JobEngineHTTP:
+		if http := job.Action.HTTP; http != nil {
+			model.HTTP = &JobHTTPModel{
+				Method:  types.StringValue(http.Method),
+				URL:     types.StringValue(http.URL),
+				Headers: stringMapValue(http.Headers),
+				Body:    optionalStringValue(http.Body),
+			}
+		}
+	}
+}
+
+func mapJobStatusToModel(model *JobResourceModel, status *aidboxclient.AidboxJobStatus) {
+	if status == nil {
+		model.LastStatus = types.StringNull()
+		model.LastRunAt = types.StringNull()
+		model.LastError = types.StringNull()
+		model.NextRunAt = types.StringNull()
+		return
+	}
+
+	model.LastStatus = optionalStringValue(status.Status)
+	model.LastRunAt = optionalStringValue(status.Start)
+	model.LastError = optionalStringValue(status.ErrorMessage())
+	model.NextRunAt = optionalStringValue(status.NextStart)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestJobIntervalSeconds(t *testing.T) {
+	testCases := map[string]int64{
+		"15m":  900,
+		"6h":   21600,
+		"90s":  90,
+		"1.5s": -1,
+		"0s":   -1,
+		"1d":   -1,
+	}
+	for every, want := range testCases {
+		got, err := jobIntervalSeconds(every)
+		if want < 0 {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %d", every, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("%s: expected %d, got %d, %v", every, want, got, err)
+		}
+	}
+}
+
+func TestJobRoundTrip(t *testing.T) {
+	model := JobResourceModel{
+		ID:    types.StringValue("cleanup"),
+		Every: types.StringValue("1h"),
+		At:    types.StringNull(),
+		SQL:   types.StringNull(),
+		RPC:   &JobRPCModel{Method: types.StringValue("aidbox.bulk/cleanup"), Params: types.StringValue(`{"days": 30, "dry-run": false}`)},
+	}
+
+	job, diags := jobFromModel(context.Background(), model)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if job.Every != 3600 || job.Action.Engine != "rpc" || job.Action.RPC.Method != "aidbox.bulk/cleanup" {
+		t.Fatalf("unexpected job %+v", job)
+	}
+
+	// The server returns the parameters reformatted.
+	job.Action.RPC.Params = json.RawMessage(`{"dry-run":false,"days":30}`)
+	mapJobToModel(&model, job)
+	if model.Every.ValueString() != "1h" {
+		t.Errorf("expected the configured interval to be kept, got %s", model.Every)
+	}
+	if model.RPC.Params.ValueString() != `{"days": 30, "dry-run": false}` {
+		t.Errorf("expected the configured params to be kept, got %s", model.RPC.Params)
+	}
+
+	job.Every = 1800
+	mapJobToModel(&model, job)
+	if model.Every.ValueString() != "1800s" {
+		t.Errorf("expected a changed interval to be read back, got %s", model.Every)
+	}
+}
+
+func TestJobHTTPHeaders(t *testing.T) {
+	ctx := context.Background()
+	r := &JobResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	httpType := objectType.AttributeTypes["http"].(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "ping")
+	values["every"] = tftypes.NewValue(tftypes.String, "1h")
+	values["http"] = tftypes.NewValue(httpType, map[string]tftypes.Value{
+		"method":  tftypes.NewValue(tftypes.String, "POST"),
+		"url":     tftypes.NewValue(tftypes.String, "https://hooks.example.org/ping"),
+		"headers": tftypes.NewValue(httpType.AttributeTypes["headers"], tftypes.UnknownValue),
+		"body":    tftypes.NewValue(tftypes.String, nil),
+	})
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+
+	var model JobResourceModel
+	if diags := plan.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("expected unknown headers to decode, got %v", diags)
+	}
+	if !model.HTTP.Headers.IsUnknown() {
+		t.Errorf("expected unknown headers, got %s", model.HTTP.Headers)
+	}
+
+	model.HTTP.Headers = types.MapValueMust(types.StringType, map[string]attr.Value{
+		"Authorization": types.StringValue("Bearer secret"),
+	})
+	job, diags := jobFromModel(ctx, model)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if job.Action.HTTP.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("unexpected headers %v", job.Action.HTTP.Headers)
+	}
+
+	mapJobToModel(&model, job)
+	if !model.HTTP.Headers.Equal(types.MapValueMust(types.StringType, map[string]attr.Value{
+		"Authorization": types.StringValue("Bearer secret"),
+	})) {
+		t.Errorf("unexpected headers %s", model.HTTP.Headers)
+	}
+
+	job.Action.HTTP.Headers = nil
+	mapJobToModel(&model, job)
+	if !model.HTTP.Headers.IsNull() {
+		t.Errorf("expected absent headers to be null, got %s", model.HTTP.Headers)
+	}
+}
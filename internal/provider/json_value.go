@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// jsonStringValidator validates that a string attribute is a JSON document.
+type jsonStringValidator struct{}
+
+var _ validator.String = jsonStringValidator{}
+
+func (v jsonStringValidator) Description(ctx context.Context) string {
+	return "value must be a valid JSON document"
+}
+
+func (v jsonStringValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v jsonStringValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if !json.Valid([]byte(req.ConfigValue.ValueString())) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid JSON", "The value must be a valid JSON document.")
+	}
+}
+
+// jsonStringValue maps a JSON document returned by the API to a string
+// attribute. The prior value is kept when it is semantically equal, so that
+// formatting and key order differences do not produce a diff.
+func jsonStringValue(prior types.String, raw []byte) types.String {
+	if len(raw) == 0 || string(raw) == "null" {
+		return types.StringNull()
+	}
+
+	if !prior.IsNull() && !prior.IsUnknown() && jsonEqual([]byte(prior.ValueString()), raw) {
+		return prior
+	}
+	return types.StringValue(string(raw))
+}
+
+// jsonEqual reports whether a and b are the same JSON value.
+func jsonEqual(a, b []byte) bool {
+	var va, vb interface{}
+	if err := json.Unmarshal(a, &va); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// jsonObjectValue maps a resource returned by the API to a JSON document
+// attribute. Only the top-level keys of the prior value are compared, so that
+// elements added by the server, such as a generated snapshot, do not produce
+// a diff. The ignore keys are never reported.
+func jsonObjectValue(prior types.String, raw []byte, ignore ...string) (types.String, error) {
+	var remote map[string]interface{}
+	if err := json.Unmarshal(raw, &remote); err != nil {
+		return prior, err
+	}
+	for _, key := range ignore {
+		delete(remote, key)
+	}
+
+	var local map[string]interface{}
+	if !prior.IsNull() && !prior.IsUnknown() && json.Unmarshal([]byte(prior.ValueString()), &local) == nil {
+		subset := make(map[string]interface{}, len(local))
+		for key := range local {
+			if value, ok := remote[key]; ok {
+				subset[key] = value
+			}
+		}
+		for _, key := range ignore {
+			if value, ok := local[key]; ok {
+				subset[key] = value
+			}
+		}
+		if reflect.DeepEqual(subset, local) {
+			return prior, nil
+		}
+		remote = subset
+	}
+
+	b, err := json.Marshal(remote)
+	if err != nil {
+		return prior, err
+	}
+	return types.StringValue(string(b)), nil
+}
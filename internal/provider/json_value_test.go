@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestJSONStringValue(t *testing.T) {
+	prior := types.StringValue(`{"match": {"gender": "female", "active": true}}`)
+
+	if got := jsonStringValue(prior, []byte(`{"match":{"active":true,"gender":"female"}}`)); !got.Equal(prior) {
+		t.Errorf("expected the prior value to be kept, got %s", got)
+	}
+
+	changed := `{"match":{"gender":"male"}}`
+	if got := jsonStringValue(prior, []byte(changed)); got.ValueString() != changed {
+		t.Errorf("expected %s, got %s", changed, got)
+	}
+
+	if got := jsonStringValue(prior, nil); !got.IsNull() {
+		t.Errorf("expected null, got %s", got)
+	}
+}
+
+func TestJSONObjectValue(t *testing.T) {
+	prior := types.StringValue(`{"url": "http://example.org/sd/us-patient", "type": "Patient"}`)
+	remote := []byte(`{"resourceType": "StructureDefinition", "id": "us-patient", "meta": {"versionId": "2"},
+		"url": "http://example.org/sd/us-patient", "type": "Patient", "snapshot": {"element": []}}`)
+
+	got, err := jsonObjectValue(prior, remote, "resourceType", "id", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(prior) {
+		t.Errorf("expected server-added elements to be ignored, got %s", got)
+	}
+
+	changed := []byte(`{"url": "http://example.org/sd/us-patient", "type": "Observation", "snapshot": {}}`)
+	got, err = jsonObjectValue(prior, changed, "resourceType", "id", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"type":"Observation","url":"http://example.org/sd/us-patient"}`; got.ValueString() != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	// Without a prior value, e.g. after an import, everything but the
+	// ignored keys is reported.
+	got, err = jsonObjectValue(types.StringNull(), remote, "resourceType", "id", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"snapshot":{"element":[]},"type":"Patient","url":"http://example.org/sd/us-patient"}`; got.ValueString() != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
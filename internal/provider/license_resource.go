@@ -128,19 +128,8 @@ func (r *LicenseResource) Schema(ctx context.Context, req resource.SchemaRequest
 }
 
 func (r *LicenseResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
-	// Prevent panic if the provider has not been configured.
-	if req.ProviderData == nil {
-		return
-	}
-
-	data, ok := req.ProviderData.(*ProviderData)
-
-	if !ok {
-		resp.Diagnostics.AddError(
-			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
-		)
-
+	data := portalProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
 		return
 	}
 
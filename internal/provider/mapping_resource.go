@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &MappingResource{}
+var _ resource.ResourceWithImportState = &MappingResource{}
+
+func NewMappingResource() resource.Resource {
+	return &MappingResource{}
+}
+
+// MappingResource defines the resource implementation.
+type MappingResource struct {
+	client Client
+}
+
+// MappingResourceModel describes the resource data model.
+type MappingResourceModel struct {
+	ID   types.String `tfsdk:"id"`
+	Body types.String `tfsdk:"body"`
+}
+
+func (r *MappingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mapping"
+}
+
+func (r *MappingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a `Mapping`, a [JUTE](https://github.com/HealthSamurai/jute.clj) template that transforms its input, " +
+			"e.g. a parsed HL7v2 message, into a FHIR transaction Bundle. Use `aidbox_hl7v2_parse` to try a mapping on a sample message",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Mapping resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "JUTE template as a JSON document, e.g. `jsonencode(...)` or `jsonencode(yamldecode(file(...)))`",
+				Required:            true,
+				Validators: []validator.String{
+					jsonStringValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *MappingResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *MappingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model MappingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "Mapping", model.ID.ValueString(), mappingFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save Mapping", fmt.Sprintf("Unable to save Mapping %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MappingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model MappingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var mapping aidboxclient.Mapping
+	err := r.client.GetResource(ctx, "Mapping", model.ID.ValueString(), &mapping)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Mapping not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Mapping", fmt.Sprintf("Unable to fetch Mapping %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	model.Body = jsonStringValue(model.Body, mapping.Body)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MappingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model MappingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "Mapping", model.ID.ValueString(), mappingFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save Mapping", fmt.Sprintf("Unable to save Mapping %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *MappingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model MappingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "Mapping", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Mapping",
+			fmt.Sprintf("Error while trying to delete the Mapping with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *MappingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func mappingFromModel(model MappingResourceModel) aidboxclient.Mapping {
+	return aidboxclient.Mapping{
+		ResourceType: "Mapping",
+		ID:           model.ID.ValueString(),
+		Body:         json.RawMessage(model.Body.ValueString()),
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestMappingCreateRead(t *testing.T) {
+	ctx := context.Background()
+	client := newFakeClient()
+	r := &MappingResource{client: client}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	schema := schemaResp.Schema
+	objectType := schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	body := `{
+  "resourceType": "Bundle",
+  "type": "transaction",
+  "entry": "$ parsed.message"
+}`
+	plan := tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "adt-to-fhir"),
+		"body": tftypes.NewValue(tftypes.String, body),
+	})
+
+	createResp := resource.CreateResponse{State: tfsdk.State{Schema: schema, Raw: tftypes.NewValue(objectType, nil)}}
+	r.Create(ctx, resource.CreateRequest{Plan: tfsdk.Plan{Schema: schema, Raw: plan}}, &createResp)
+	if createResp.Diagnostics.HasError() {
+		t.Fatal(createResp.Diagnostics)
+	}
+
+	var saved map[string]interface{}
+	if err := json.Unmarshal(client.resources["Mapping/adt-to-fhir"], &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved["resourceType"] != "Mapping" || saved["id"] != "adt-to-fhir" {
+		t.Errorf("unexpected mapping %v", saved)
+	}
+
+	read := func(state tftypes.Value) resource.ReadResponse {
+		resp := resource.ReadResponse{State: tfsdk.State{Schema: schema, Raw: state}}
+		r.Read(ctx, resource.ReadRequest{State: tfsdk.State{Schema: schema, Raw: state}}, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		return resp
+	}
+	readBody := func(resp resource.ReadResponse) string {
+		var model MappingResourceModel
+		if diags := resp.State.Get(ctx, &model); diags.HasError() {
+			t.Fatal(diags)
+		}
+		return model.Body.ValueString()
+	}
+
+	// The fake stores the body compacted, as the instance returns it
+	// reformatted; the configured formatting is kept as long as the document
+	// is the same.
+	readResp := read(createResp.State.Raw)
+	if got := readBody(readResp); got != body {
+		t.Errorf("expected the configured body to be kept, got %s", got)
+	}
+
+	changed := `{"resourceType":"Bundle","type":"batch"}`
+	client.resources["Mapping/adt-to-fhir"] = json.RawMessage(`{"resourceType":"Mapping","id":"adt-to-fhir","body":` + changed + `}`)
+	readResp = read(readResp.State.Raw)
+	if got := readBody(readResp); got != changed {
+		t.Errorf("expected the changed body, got %s", got)
+	}
+
+	delete(client.resources, "Mapping/adt-to-fhir")
+	readResp = read(readResp.State.Raw)
+	if !readResp.State.Raw.IsNull() {
+		t.Error("expected a missing mapping to be removed from state")
+	}
+}
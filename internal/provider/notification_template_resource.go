@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &NotificationTemplateResource{}
+var _ resource.ResourceWithImportState = &NotificationTemplateResource{}
+
+func NewNotificationTemplateResource() resource.Resource {
+	return &NotificationTemplateResource{}
+}
+
+// NotificationTemplateResource defines the resource implementation.
+type NotificationTemplateResource struct {
+	client Client
+}
+
+// NotificationTemplateResourceModel describes the resource data model.
+type NotificationTemplateResourceModel struct {
+	ID       types.String `tfsdk:"id"`
+	Subject  types.String `tfsdk:"subject"`
+	Template types.String `tfsdk:"template"`
+}
+
+func (r *NotificationTemplateResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_notification_template"
+}
+
+func (r *NotificationTemplateResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a `NotificationTemplate`, the subject and body of an email Aidbox sends, e.g. for password resets or signups. " +
+			"Both are [Handlebars](https://handlebarsjs.com/) templates",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "NotificationTemplate resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "Subject template, e.g. `Reset your password, {{user.name}}`",
+				Required:            true,
+				Validators: []validator.String{
+					handlebarsValidator{},
+				},
+			},
+			"template": schema.StringAttribute{
+				MarkdownDescription: "Body template, usually HTML",
+				Required:            true,
+				Validators: []validator.String{
+					handlebarsValidator{},
+				},
+			},
+		},
+	}
+}
+
+func (r *NotificationTemplateResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *NotificationTemplateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model NotificationTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "NotificationTemplate", model.ID.ValueString(), notificationTemplateFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save NotificationTemplate", fmt.Sprintf("Unable to save NotificationTemplate %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *NotificationTemplateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model NotificationTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var template aidboxclient.NotificationTemplate
+	err := r.client.GetResource(ctx, "NotificationTemplate", model.ID.ValueString(), &template)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "NotificationTemplate not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch NotificationTemplate", fmt.Sprintf("Unable to fetch NotificationTemplate %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	model.Subject = types.StringValue(template.Subject)
+	model.Template = types.StringValue(template.Template)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *NotificationTemplateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model NotificationTemplateResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "NotificationTemplate", model.ID.ValueString(), notificationTemplateFromModel(model), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Save NotificationTemplate", fmt.Sprintf("Unable to save NotificationTemplate %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *NotificationTemplateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model NotificationTemplateResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "NotificationTemplate", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete NotificationTemplate",
+			fmt.Sprintf("Error while trying to delete the NotificationTemplate with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *NotificationTemplateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func notificationTemplateFromModel(model NotificationTemplateResourceModel) aidboxclient.NotificationTemplate {
+	return aidboxclient.NotificationTemplate{
+		ResourceType: "NotificationTemplate",
+		ID:           model.ID.ValueString(),
+		Subject:      model.Subject.ValueString(),
+		Template:     model.Template.ValueString(),
+	}
+}
+
+// handlebarsValidator checks that a string attribute is a well-formed
+// Handlebars template: tags are closed and block helpers are balanced.
+type handlebarsValidator struct{}
+
+var _ validator.String = handlebarsValidator{}
+
+func (v handlebarsValidator) Description(ctx context.Context) string {
+	return "value must be a well-formed Handlebars template"
+}
+
+func (v handlebarsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v handlebarsValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := checkHandlebars(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Handlebars Template", err.Error())
+	}
+}
+
+// checkHandlebars reports unterminated tags and unbalanced block helpers,
+// e.g. an {{#if}} without {{/if}}. Expressions themselves are not checked.
+func checkHandlebars(template string) error {
+	var blocks []string
+	rest := template
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		rest = rest[start:]
+
+		// Comments may contain "}}" when written as {{!-- --}}.
+		closing := "}}"
+		if strings.HasPrefix(rest, "{{!--") {
+			closing = "--}}"
+		}
+		end := strings.Index(rest, closing)
+		if end < 0 {
+			return fmt.Errorf("unterminated tag %q", truncate(rest, 20))
+		}
+
+		tag := strings.Trim(rest[2:end], "{}~ \t\r\n")
+		rest = rest[end+len(closing):]
+
+		switch {
+		case strings.HasPrefix(tag, "#"), strings.HasPrefix(tag, "^") && len(tag) > 1:
+			// Partial blocks ({{#> layout}}) and decorators ({{#*inline}})
+			// are closed by their name alone.
+			name := strings.TrimLeft(tag[1:], ">* \t")
+			name, _, _ = strings.Cut(name, " ")
+			blocks = append(blocks, name)
+		case strings.HasPrefix(tag, "/"):
+			name := strings.TrimSpace(tag[1:])
+			if len(blocks) == 0 {
+				return fmt.Errorf("{{/%s}} closes a block that was not opened", name)
+			}
+			if open := blocks[len(blocks)-1]; open != name {
+				return fmt.Errorf("{{/%s}} closes {{#%s}}", name, open)
+			}
+			blocks = blocks[:len(blocks)-1]
+		}
+	}
+
+	if len(blocks) > 0 {
+		return fmt.Errorf("{{#%s}} is not closed", blocks[len(blocks)-1])
+	}
+	return nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
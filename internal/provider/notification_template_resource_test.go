@@ -0,0 +1,30 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import "testing"
+
+func TestCheckHandlebars(t *testing.T) {
+	testCases := map[string]string{
+		`Hello {{user.name}}`:                                                      "",
+		`{{#if user.name}}Hi {{user.name}}{{else}}Hi{{/if}}`:                       "",
+		`{{#each items}}{{#with this}}{{name}}{{/with}}{{/each}}`:                  "",
+		`{{{link}}} {{!-- {{#if}} in a comment --}} {{~#unless a~}}x{{~/unless~}}`: "",
+		`{{#> layout}}body{{/layout}}`:                                             "",
+		`Hello {{user.name`:                                                        `unterminated tag "{{user.name"`,
+		`{{#if a}}x`:                                                               "{{#if}} is not closed",
+		`{{#if a}}{{#each b}}{{/if}}{{/each}}`:                                     "{{/if}} closes {{#each}}",
+		`x{{/if}}`:                                                                 "{{/if}} closes a block that was not opened",
+	}
+	for template, want := range testCases {
+		err := checkHandlebars(template)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != want {
+			t.Errorf("%s: expected %q, got %q", template, want, got)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// operationOutcomeDiagnostics reports err against attribute. When Aidbox
+// answered with an OperationOutcome, each issue becomes a diagnostic naming
+// the offending element instead of a raw response body.
+func operationOutcomeDiagnostics(err error, attribute path.Path, summary string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	outcome := aidboxclient.OperationOutcomeFromError(err)
+	if outcome == nil {
+		diags.AddAttributeError(attribute, summary, err.Error())
+		return diags
+	}
+
+	for _, issue := range outcome.Issue {
+		detail := issue.Message()
+		if len(issue.Expression) > 0 {
+			detail = fmt.Sprintf("%s: %s", strings.Join(issue.Expression, ", "), detail)
+		}
+
+		switch issue.Severity {
+		case "warning", "information":
+			diags.AddAttributeWarning(attribute, summary, detail)
+		default:
+			diags.AddAttributeError(attribute, summary, detail)
+		}
+	}
+
+	// An outcome made only of warnings still failed the request.
+	if !diags.HasError() {
+		diags.AddAttributeError(attribute, summary, err.Error())
+	}
+	return diags
+}
@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestOperationOutcomeDiagnostics(t *testing.T) {
+	err := &aidboxclient.APIError{
+		StatusCode: 422,
+		Status:     "422 Unprocessable Entity",
+		Body: `{"resourceType": "OperationOutcome", "issue": [
+			{"severity": "error", "code": "invalid", "expression": ["StructureDefinition.differential.element[1].path"], "diagnostics": "unknown element Patient.foo"},
+			{"severity": "warning", "code": "informational", "details": {"text": "no snapshot"}}]}`,
+	}
+
+	diags := operationOutcomeDiagnostics(err, path.Root("content"), "Invalid StructureDefinition")
+	if diags.ErrorsCount() != 1 || diags.WarningsCount() != 1 {
+		t.Fatalf("expected one error and one warning, got %v", diags)
+	}
+	if detail := diags.Errors()[0].Detail(); !strings.HasPrefix(detail, "StructureDefinition.differential.element[1].path: unknown element") {
+		t.Errorf("unexpected detail %q", detail)
+	}
+
+	// Other errors are reported as they are.
+	diags = operationOutcomeDiagnostics(errors.New("connection refused"), path.Root("content"), "Invalid StructureDefinition")
+	if diags.ErrorsCount() != 1 || diags.Errors()[0].Detail() != "connection refused" {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &OperationResource{}
+var _ resource.ResourceWithImportState = &OperationResource{}
+
+var operationMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+
+// operationPathSegmentRegexp matches a literal path segment or a named
+// parameter such as ":id".
+var operationPathSegmentRegexp = regexp.MustCompile(`^(:[A-Za-z_][A-Za-z0-9_-]*|[^:/\s][^/\s]*)$`)
+
+func NewOperationResource() resource.Resource {
+	return &OperationResource{}
+}
+
+// OperationResource defines the resource implementation.
+type OperationResource struct {
+	client Client
+}
+
+// OperationResourceModel describes the resource data model.
+type OperationResourceModel struct {
+	ID     types.String   `tfsdk:"id"`
+	Method types.String   `tfsdk:"method"`
+	Path   []types.String `tfsdk:"path"`
+	Action types.String   `tfsdk:"action"`
+	AppID  types.String   `tfsdk:"app_id"`
+}
+
+func (r *OperationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_operation"
+}
+
+func (r *OperationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a standalone Aidbox `Operation`, a custom route of the box handled by an action or an app",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Operation resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"method": operationMethodSchema(),
+			"path":   operationPathSchema(),
+			"action": schema.StringAttribute{
+				MarkdownDescription: "Handler of the operation, e.g. `proto.operations/fhir-search`",
+				Optional:            true,
+			},
+			"app_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the `App` serving the operation",
+				Optional:            true,
+			},
+		},
+	}
+}
+
+func (r *OperationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *OperationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model OperationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *OperationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model OperationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var operation aidboxclient.Operation
+	err := r.client.GetResource(ctx, "Operation", model.ID.ValueString(), &operation)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "Operation not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Operation", fmt.Sprintf("Unable to fetch Operation %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	if len(operation.Request) == 0 {
+		resp.Diagnostics.AddError("Failed to Fetch Operation", fmt.Sprintf("Operation %s has no request route.", model.ID.ValueString()))
+		return
+	}
+
+	model.Method = types.StringValue(strings.ToUpper(operation.Request[0].Literal))
+	model.Path = operationPathToModel(operation.Request[1:])
+	model.Action = optionalStringValue(operation.Action)
+	model.AppID = types.StringNull()
+	if operation.App != nil {
+		model.AppID = types.StringValue(operation.App.ID)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *OperationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model OperationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *OperationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model OperationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "Operation", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Operation",
+			fmt.Sprintf("Error while trying to delete the Operation with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *OperationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *OperationResource) save(ctx context.Context, model OperationResourceModel, diags *diag.Diagnostics) {
+	operation := aidboxclient.Operation{
+		ResourceType: "Operation",
+		ID:           model.ID.ValueString(),
+		Request:      append([]aidboxclient.PathSegment{{Literal: strings.ToLower(model.Method.ValueString())}}, operationPathFromModel(model.Path)...),
+		Action:       model.Action.ValueString(),
+	}
+	if !model.AppID.IsNull() {
+		operation.App = &aidboxclient.Reference{ID: model.AppID.ValueString(), ResourceType: "App"}
+	}
+
+	if err := r.client.PutResource(ctx, "Operation", operation.ID, operation, nil); err != nil {
+		diags.AddError("Failed to Save Operation", fmt.Sprintf("Unable to save Operation %s: %s", operation.ID, err))
+	}
+}
+
+func operationMethodSchema() schema.StringAttribute {
+	return schema.StringAttribute{
+		MarkdownDescription: "HTTP method, one of `" + strings.Join(operationMethods, "`, `") + "`",
+		Required:            true,
+		Validators: []validator.String{
+			stringvalidator.OneOf(operationMethods...),
+		},
+	}
+}
+
+func operationPathSchema() schema.ListAttribute {
+	return schema.ListAttribute{
+		MarkdownDescription: "Route segments, e.g. `[\"Patient\", \":id\", \"$report\"]`. Segments starting with `:` are named parameters",
+		ElementType:         types.StringType,
+		Required:            true,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+			listvalidator.ValueStringsAre(stringvalidator.RegexMatches(operationPathSegmentRegexp, "must be a path segment or a named parameter such as :id")),
+		},
+	}
+}
+
+// operationPathFromModel converts route segments, where ":name" denotes a
+// named parameter, to the Aidbox representation.
+func operationPathFromModel(segments []types.String) []aidboxclient.PathSegment {
+	result := make([]aidboxclient.PathSegment, 0, len(segments))
+	for _, s := range segments {
+		if name, ok := strings.CutPrefix(s.ValueString(), ":"); ok {
+			result = append(result, aidboxclient.PathSegment{Name: name})
+		} else {
+			result = append(result, aidboxclient.PathSegment{Literal: s.ValueString()})
+		}
+	}
+	return result
+}
+
+func operationPathToModel(segments []aidboxclient.PathSegment) []types.String {
+	result := make([]types.String, 0, len(segments))
+	for _, s := range segments {
+		if s.Name != "" {
+			result = append(result, types.StringValue(":"+s.Name))
+		} else {
+			result = append(result, types.StringValue(s.Literal))
+		}
+	}
+	return result
+}
+
+// operationRoute renders a route such as "GET /Patient/:id/$report".
+func operationRoute(method string, segments []types.String) string {
+	parts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		parts = append(parts, s.ValueString())
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOperationPath(t *testing.T) {
+	model := []types.String{types.StringValue("Patient"), types.StringValue(":id"), types.StringValue("$report")}
+	expected := []aidboxclient.PathSegment{{Literal: "Patient"}, {Name: "id"}, {Literal: "$report"}}
+
+	segments := operationPathFromModel(model)
+	if !reflect.DeepEqual(segments, expected) {
+		t.Errorf("expected %v, got %v", expected, segments)
+	}
+	if back := operationPathToModel(segments); !reflect.DeepEqual(back, model) {
+		t.Errorf("expected %v, got %v", model, back)
+	}
+	if route := operationRoute("GET", model); route != "GET /Patient/:id/$report" {
+		t.Errorf("unexpected route %q", route)
+	}
+}
+
+func TestOperationPathSegmentRegexp(t *testing.T) {
+	for _, s := range []string{"Patient", ":id", "$report", "_history"} {
+		if !operationPathSegmentRegexp.MatchString(s) {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	for _, s := range []string{"", ":", "a/b", "a b", ":1x"} {
+		if operationPathSegmentRegexp.MatchString(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
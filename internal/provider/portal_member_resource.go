@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PortalMemberResource{}
+var _ resource.ResourceWithImportState = &PortalMemberResource{}
+
+var emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func NewPortalMemberResource() resource.Resource {
+	return &PortalMemberResource{}
+}
+
+// PortalMemberResource defines the resource implementation.
+type PortalMemberResource struct {
+	client Client
+}
+
+// PortalMemberResourceModel describes the resource data model.
+type PortalMemberResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	ProjectID types.String `tfsdk:"project_id"`
+	Email     types.String `tfsdk:"email"`
+	Role      types.String `tfsdk:"role"`
+	Status    types.String `tfsdk:"status"`
+}
+
+func (r *PortalMemberResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_portal_member"
+}
+
+func (r *PortalMemberResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages the access of a user to a project of the Aidbox portal. Users are invited by email; " +
+			"destroying the resource removes them from the project",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Member ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"project_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the project, e.g. `aidbox_portal_project.team.id`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"email": schema.StringAttribute{
+				MarkdownDescription: "Email address the invitation is sent to",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(emailRegexp, "must be an email address"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"role": schema.StringAttribute{
+				MarkdownDescription: "`owner`, `admin` or `member`. Defaults to `member`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("member"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("owner", "admin", "member"),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "`invited` until the user accepts the invitation, then `active`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *PortalMemberResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := portalProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *PortalMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model PortalMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	member, err := r.client.InvitePortalMember(ctx, model.ProjectID.ValueString(), model.Email.ValueString(), model.Role.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Invite Member", fmt.Sprintf("Unable to invite %s to project %s: %s", model.Email.ValueString(), model.ProjectID.ValueString(), err))
+		return
+	}
+
+	model.ID = types.StringValue(member.ID)
+	model.Status = types.StringValue(member.Status)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model PortalMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	member, err := r.client.GetPortalMember(ctx, model.ProjectID.ValueString(), model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Member", fmt.Sprintf("Unable to fetch member %s of project %s: %s", model.ID.ValueString(), model.ProjectID.ValueString(), err))
+		return
+	}
+	if member == nil {
+		tflog.Warn(ctx, "Member not found, removing from state", map[string]interface{}{"id": model.ID.ValueString(), "project_id": model.ProjectID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	model.Email = types.StringValue(member.Email)
+	model.Role = types.StringValue(member.Role)
+	model.Status = types.StringValue(member.Status)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model PortalMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the role can change in place.
+	if err := r.client.UpdatePortalMemberRole(ctx, model.ProjectID.ValueString(), model.ID.ValueString(), model.Role.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to Update Member", fmt.Sprintf("Unable to update the role of member %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	member, err := r.client.GetPortalMember(ctx, model.ProjectID.ValueString(), model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Member", fmt.Sprintf("Unable to fetch member %s of project %s: %s", model.ID.ValueString(), model.ProjectID.ValueString(), err))
+		return
+	}
+	model.Status = types.StringNull()
+	if member != nil {
+		model.Status = types.StringValue(member.Status)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model PortalMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.RemovePortalMember(ctx, model.ProjectID.ValueString(), model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Member",
+			fmt.Sprintf("Error while trying to remove the member with ID %s from project %s: %s", model.ID.ValueString(), model.ProjectID.ValueString(), err),
+		)
+	}
+}
+
+func (r *PortalMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectID, id, ok := strings.Cut(req.ID, "/")
+	if !ok || projectID == "" || id == "" {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected \"<project_id>/<member_id>\", got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("project_id"), projectID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &PortalProjectResource{}
+var _ resource.ResourceWithImportState = &PortalProjectResource{}
+
+func NewPortalProjectResource() resource.Resource {
+	return &PortalProjectResource{}
+}
+
+// PortalProjectResource defines the resource implementation.
+type PortalProjectResource struct {
+	client Client
+}
+
+// PortalProjectResourceModel describes the resource data model.
+type PortalProjectResourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	CreatedAt   types.String `tfsdk:"created_at"`
+}
+
+func (r *PortalProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_portal_project"
+}
+
+func (r *PortalProjectResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a project of the Aidbox portal. Licenses and members belong to a project",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Project ID, e.g. for `aidbox_portal_member`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"created_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *PortalProjectResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := portalProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *PortalProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model PortalProjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.CreatePortalProject(ctx, model.Name.ValueString(), model.Description.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Create Project", fmt.Sprintf("Unable to create project %s: %s", model.Name.ValueString(), err))
+		return
+	}
+
+	model.ID = types.StringValue(project.ID)
+	model.CreatedAt = types.StringValue(project.Meta.CreatedAt)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model PortalProjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	project, err := r.client.GetPortalProject(ctx, model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch Project", fmt.Sprintf("Unable to fetch project %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	if project == nil {
+		tflog.Warn(ctx, "Project not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	model.Name = types.StringValue(project.Name)
+	model.Description = optionalStringValue(project.Description)
+	model.CreatedAt = types.StringValue(project.Meta.CreatedAt)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model PortalProjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.UpdatePortalProject(ctx, aidboxclient.PortalProject{
+		ID:          model.ID.ValueString(),
+		Name:        model.Name.ValueString(),
+		Description: model.Description.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Update Project", fmt.Sprintf("Unable to update project %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *PortalProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model PortalProjectResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeletePortalProject(ctx, model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete Project",
+			fmt.Sprintf("Error while trying to delete the project with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *PortalProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
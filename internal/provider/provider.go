@@ -4,17 +4,27 @@ package provider
 
 import (
 	"context"
-	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"os" // Import for environment variables
+	"strings"
+	"sync"
 	"terraform-provider-aidbox/internal/aidboxclient"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure AidboxProvider satisfies various provider interfaces.
@@ -26,21 +36,183 @@ type AidboxProvider struct {
 }
 
 type AidboxProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
-	Token    types.String `tfsdk:"token"`
+	Endpoint           types.String `tfsdk:"endpoint"`
+	Token              types.String `tfsdk:"token"`
+	InstanceURL        types.String `tfsdk:"instance_url"`
+	ClientID           types.String `tfsdk:"client_id"`
+	ClientSecret       types.String `tfsdk:"client_secret"`
+	Profile            types.String `tfsdk:"profile"`
+	CredentialsFile    types.String `tfsdk:"credentials_file"`
+	CACertPEM          types.String `tfsdk:"ca_cert_pem"`
+	CACertFile         types.String `tfsdk:"ca_cert_file"`
+	ClientCert         types.String `tfsdk:"client_cert"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	ValidateCreds      types.Bool   `tfsdk:"validate_credentials"`
 }
 
 type Client interface {
 	CreateLicense(cxt context.Context, name, product, licenseType string) (aidboxclient.LicenseResponse, error)
 	GetLicense(ctx context.Context, licenseID string) (aidboxclient.LicenseResponse, error)
 	DeleteLicense(ctx context.Context, licenseID string) error
+	WhoAmI(ctx context.Context) (aidboxclient.PortalUser, error)
+	GetServerInfo(ctx context.Context) (aidboxclient.ServerInfo, error)
+	GetResource(ctx context.Context, collection, id string, out interface{}) error
+	PutResource(ctx context.Context, collection, id string, in, out interface{}) error
+	DeleteResource(ctx context.Context, collection, id string) error
+	Reindex(ctx context.Context, resourceType string) (string, error)
+	ExecuteSQL(ctx context.Context, query string, params ...interface{}) ([]map[string]interface{}, error)
+	CallRPC(ctx context.Context, method string, params, out interface{}) error
+	CreateDBIndex(ctx context.Context, index aidboxclient.DBIndex) error
+	DropDBIndex(ctx context.Context, schema, name string, concurrently bool) error
+	GetDBIndex(ctx context.Context, schema, name string) (*aidboxclient.DBIndexState, error)
+	SuggestIndex(ctx context.Context, resourceType, searchParam string) ([]aidboxclient.IndexSuggestion, error)
+	ApplySQLMigration(ctx context.Context, id, up string) (aidboxclient.SQLMigration, error)
+	GetSQLMigration(ctx context.Context, id string) (*aidboxclient.SQLMigration, error)
+	RevertSQLMigration(ctx context.Context, id, down string) error
+	RunQuery(ctx context.Context, name string, params map[string]string) (aidboxclient.QueryResult, error)
+	GetAsyncStatus(ctx context.Context, statusURL string) (aidboxclient.AsyncStatus, error)
+	WaitAsync(ctx context.Context, statusURL string, opts aidboxclient.PollOptions) (aidboxclient.AsyncStatus, error)
+	StartBulkImport(ctx context.Context, id, contentEncoding string, inputs []aidboxclient.BulkImportInput) (string, aidboxclient.BulkImportResult, error)
+	LoadNDJSON(ctx context.Context, sourceURL, resourceType string) (aidboxclient.BulkImportResult, error)
+	ImportNDJSON(ctx context.Context, content io.Reader, chunkSize int) (aidboxclient.BulkImportResult, error)
+	StartBulkExport(ctx context.Context, req aidboxclient.BulkExportRequest) (string, error)
+	DownloadExportFile(ctx context.Context, fileURL string, requiresAccessToken bool, w io.Writer) error
+	GetJobStatus(ctx context.Context, jobID string) (*aidboxclient.AidboxJobStatus, error)
+	GetEmailProvider(ctx context.Context, name string) (*aidboxclient.EmailProvider, error)
+	PutEmailProvider(ctx context.Context, name string, provider aidboxclient.EmailProvider) error
+	DeleteEmailProvider(ctx context.Context, name string) error
+	ParseHl7v2(ctx context.Context, message string, strict bool) (aidboxclient.Hl7v2ParseResult, error)
+	DebugMapping(ctx context.Context, body, input json.RawMessage) (json.RawMessage, error)
+	ApplyMapping(ctx context.Context, id string, input json.RawMessage) (json.RawMessage, error)
+	InstallFHIRPackage(ctx context.Context, pkg string) (string, error)
+	UploadFHIRPackage(ctx context.Context, filename string, content io.Reader) (string, error)
+	GetFHIRPackage(ctx context.Context, name string) (*aidboxclient.FHIRPackage, error)
+	UninstallFHIRPackage(ctx context.Context, name string) error
+	ExpandValueSet(ctx context.Context, valueSetURL, filter string, count int64) (aidboxclient.ValueSetExpansion, error)
+	UploadConcepts(ctx context.Context, concepts []aidboxclient.Concept, chunkSize int) error
+	CountConcepts(ctx context.Context, codeSystemID, system string) (int64, error)
+	DeleteConcepts(ctx context.Context, codeSystemID string) error
+	CreateBox(ctx context.Context, box aidboxclient.Box) (aidboxclient.Box, error)
+	GetBox(ctx context.Context, id string) (aidboxclient.Box, error)
+	DeleteBox(ctx context.Context, id string) error
+	BoxURL(box aidboxclient.Box) (string, error)
+	CreatePortalProject(ctx context.Context, name, description string) (aidboxclient.PortalProject, error)
+	GetPortalProject(ctx context.Context, id string) (*aidboxclient.PortalProject, error)
+	UpdatePortalProject(ctx context.Context, project aidboxclient.PortalProject) error
+	DeletePortalProject(ctx context.Context, id string) error
+	InvitePortalMember(ctx context.Context, projectID, email, role string) (aidboxclient.PortalMember, error)
+	GetPortalMember(ctx context.Context, projectID, id string) (*aidboxclient.PortalMember, error)
+	UpdatePortalMemberRole(ctx context.Context, projectID, id, role string) error
+	RemovePortalMember(ctx context.Context, projectID, id string) error
+	CreateHostedInstance(ctx context.Context, licenseID, name, region string) (aidboxclient.HostedInstance, error)
+	GetHostedInstance(ctx context.Context, id string) (*aidboxclient.HostedInstance, error)
+	DeleteHostedInstance(ctx context.Context, id string) error
 }
 
 // This structure holds the configuration data which can be used across resources
 type ProviderData struct {
-	Endpoint string
-	Token    string
-	Client   Client
+	Endpoint    string
+	Token       string
+	InstanceURL string
+	Client      Client
+	// HTTPClient is the configured HTTP client, for ad-hoc requests.
+	HTTPClient *http.Client
+	// ConfigUnknown is set while the credentials depend on values that are
+	// not known yet, e.g. the outputs of an aidbox_box. Requests fail until
+	// the provider is configured again with known values.
+	ConfigUnknown bool
+
+	serverInfoOnce sync.Once
+	serverInfo     *aidboxclient.ServerInfo
+}
+
+// ServerInfo returns the version and capabilities of the connected instance,
+// probing it on first use. It is nil when no instance is configured or it
+// could not be probed.
+func (d *ProviderData) ServerInfo(ctx context.Context) *aidboxclient.ServerInfo {
+	d.serverInfoOnce.Do(func() {
+		if d.InstanceURL == "" || d.ConfigUnknown {
+			return
+		}
+		serverInfo, err := d.Client.GetServerInfo(ctx)
+		if err != nil {
+			tflog.Warn(ctx, "Unable to detect Aidbox instance version", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		d.setServerInfo(ctx, serverInfo)
+	})
+	return d.serverInfo
+}
+
+func (d *ProviderData) setServerInfo(ctx context.Context, serverInfo aidboxclient.ServerInfo) {
+	tflog.Debug(ctx, "Detected Aidbox instance", map[string]interface{}{
+		"version":        serverInfo.Version.Version,
+		"channel":        serverInfo.Version.Channel,
+		"resource_types": len(serverInfo.ResourceTypes),
+	})
+	d.serverInfo = &serverInfo
+}
+
+// instanceProviderData returns the provider data of resources and data
+// sources that manage an Aidbox instance, or nil when the provider has not
+// been configured yet.
+func instanceProviderData(providerData interface{}, diags *diag.Diagnostics) *ProviderData {
+	// Prevent panic if the provider has not been configured.
+	if providerData == nil {
+		return nil
+	}
+
+	data, ok := providerData.(*ProviderData)
+	if !ok {
+		diags.AddError(
+			"Unexpected Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil
+	}
+
+	if data.InstanceURL == "" && !data.ConfigUnknown {
+		diags.AddError(
+			"No Aidbox Instance Configured",
+			"This resource manages an Aidbox instance. Please provide 'instance_url', 'client_id' and 'client_secret' "+
+				"in the provider configuration, through the AIDBOX_INSTANCE_URL, AIDBOX_CLIENT_ID and AIDBOX_CLIENT_SECRET "+
+				"environment variables, or in a credentials profile.",
+		)
+		return nil
+	}
+
+	return data
+}
+
+// portalProviderData returns the provider data of resources that manage the
+// Aidbox portal, or nil when the provider has not been configured yet.
+func portalProviderData(providerData interface{}, diags *diag.Diagnostics) *ProviderData {
+	// Prevent panic if the provider has not been configured.
+	if providerData == nil {
+		return nil
+	}
+
+	data, ok := providerData.(*ProviderData)
+	if !ok {
+		diags.AddError(
+			"Unexpected Configure Type",
+			fmt.Sprintf("Expected *ProviderData, got: %T. Please report this issue to the provider developers.", providerData),
+		)
+		return nil
+	}
+
+	if data.Token == "" && !data.ConfigUnknown {
+		diags.AddError(
+			"No Token Provided",
+			"This resource manages the Aidbox portal and requires a portal token. Please provide a 'token' in the provider configuration, "+
+				"through the 'AIDBOX_TOKEN' environment variable or in a credentials profile.",
+		)
+		return nil
+	}
+
+	return data
 }
 
 func (p *AidboxProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -52,11 +224,68 @@ func (p *AidboxProvider) Schema(ctx context.Context, req provider.SchemaRequest,
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Aidbox RPC API endpoint",
+				MarkdownDescription: "Aidbox RPC API endpoint. Can also be set with the `AIDBOX_ENDPOINT` environment variable. Defaults to `https://aidbox.app/rpc`",
 				Optional:            true,
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "Aidbox token",
+				MarkdownDescription: "Aidbox token. Can also be set with the `AIDBOX_TOKEN` environment variable",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"instance_url": schema.StringAttribute{
+				MarkdownDescription: "Base URL of an Aidbox instance, e.g. `https://mybox.aidbox.app`. Can also be set with the `AIDBOX_INSTANCE_URL` environment variable",
+				Optional:            true,
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "ID of the Aidbox `Client` used to authenticate against the instance. Can also be set with the `AIDBOX_CLIENT_ID` environment variable",
+				Optional:            true,
+			},
+			"client_secret": schema.StringAttribute{
+				MarkdownDescription: "Secret of the Aidbox `Client` used to authenticate against the instance. Can also be set with the `AIDBOX_CLIENT_SECRET` environment variable",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Named profile to read from the credentials file. Can also be set with the `AIDBOX_PROFILE` environment variable. Defaults to `default`",
+				Optional:            true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path to an INI or YAML credentials file. Can also be set with the `AIDBOX_CREDENTIALS_FILE` environment variable. Defaults to `~/.aidbox/credentials`",
+				Optional:            true,
+			},
+			"ca_cert_pem": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded CA certificate bundle trusted in addition to the system roots",
+				Optional:            true,
+			},
+			"ca_cert_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a PEM-encoded CA certificate bundle trusted in addition to the system roots",
+				Optional:            true,
+			},
+			"client_cert": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded client certificate used for mutual TLS",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_key")),
+				},
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM-encoded private key of the client certificate used for mutual TLS",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("client_cert")),
+				},
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Disable TLS certificate verification. **Never use this in production.**",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "HTTP proxy URL. Defaults to the `HTTPS_PROXY`/`HTTP_PROXY` environment variables",
+				Optional:            true,
+			},
+			"validate_credentials": schema.BoolAttribute{
+				MarkdownDescription: "Verify the portal token and instance credentials with a cheap authenticated call when the provider is configured, and fail if the instance version cannot be detected",
 				Optional:            true,
 			},
 		},
@@ -72,46 +301,234 @@ func (p *AidboxProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
-	// Set default endpoint if not provided
-	if data.Endpoint.IsNull() || data.Endpoint.IsUnknown() || data.Endpoint.ValueString() == "" {
-		defaultEndpoint := basetypes.NewStringValue("https://aidbox.app/rpc")
-		data.Endpoint = defaultEndpoint
+	// Credentials taken from other resources are unknown until they are
+	// created. Falling back to the environment or a profile would target the
+	// wrong server, so defer to apply, when the provider is configured again.
+	if data.InstanceURL.IsUnknown() || data.ClientID.IsUnknown() || data.ClientSecret.IsUnknown() || data.Token.IsUnknown() {
+		tflog.Debug(ctx, "Provider credentials are unknown, deferring configuration to apply")
+		client := aidboxclient.NewClient("", "", nil)
+		providerData := &ProviderData{
+			Client:        client,
+			HTTPClient:    client.Client,
+			ConfigUnknown: true,
+		}
+		resp.DataSourceData = providerData
+		resp.ResourceData = providerData
+		return
+	}
+
+	// Resolve the credentials profile; explicit profiles must exist, the
+	// implicit default profile is optional.
+	profileName := stringValueOrEnv(data.Profile, "AIDBOX_PROFILE")
+	explicitProfile := profileName != ""
+	if !explicitProfile {
+		profileName = aidboxclient.DefaultProfileName
+	}
+
+	credentialsFile := stringValueOrEnv(data.CredentialsFile, "AIDBOX_CREDENTIALS_FILE")
+	explicitFile := credentialsFile != ""
+	if !explicitFile {
+		defaultFile, err := aidboxclient.DefaultCredentialsFile()
+		if err != nil && explicitProfile {
+			resp.Diagnostics.AddError("Unable to Locate Credentials File", err.Error())
+			return
+		}
+		credentialsFile = defaultFile
+	}
+
+	var profile aidboxclient.Profile
+	if credentialsFile != "" {
+		if _, statErr := os.Stat(credentialsFile); statErr == nil || explicitProfile || explicitFile {
+			loaded, err := aidboxclient.LoadProfile(credentialsFile, profileName)
+			switch {
+			case err == nil:
+				profile = loaded
+			case !explicitProfile && errors.Is(err, aidboxclient.ErrProfileNotFound):
+				// The default profile is optional.
+			default:
+				resp.Diagnostics.AddAttributeError(
+					path.Root("profile"),
+					"Unable to Load Credentials Profile",
+					fmt.Sprintf("Unable to load profile %q: %s", profileName, err),
+				)
+				return
+			}
+		}
+	}
+
+	// Precedence: provider configuration, environment variables, profile, default.
+	endpoint := firstNonEmpty(stringValueOrEnv(data.Endpoint, "AIDBOX_ENDPOINT"), profile.Endpoint, "https://aidbox.app/rpc")
+	token := firstNonEmpty(stringValueOrEnv(data.Token, "AIDBOX_TOKEN"), profile.Token)
+	instanceURL := strings.TrimSuffix(firstNonEmpty(stringValueOrEnv(data.InstanceURL, "AIDBOX_INSTANCE_URL"), profile.InstanceURL), "/")
+	clientID := firstNonEmpty(stringValueOrEnv(data.ClientID, "AIDBOX_CLIENT_ID"), profile.ClientID)
+	clientSecret := firstNonEmpty(stringValueOrEnv(data.ClientSecret, "AIDBOX_CLIENT_SECRET"), profile.ClientSecret)
+
+	if token == "" && instanceURL == "" {
+		resp.Diagnostics.AddError(
+			"No Credentials Provided",
+			"Please provide a 'token' for the Aidbox portal and/or an 'instance_url' with 'client_id' and 'client_secret' "+
+				"in the provider configuration, through the AIDBOX_TOKEN, AIDBOX_INSTANCE_URL, AIDBOX_CLIENT_ID and "+
+				"AIDBOX_CLIENT_SECRET environment variables, or in a credentials profile.",
+		)
+		return
+	}
+
+	if instanceURL != "" && (clientID == "" || clientSecret == "") {
+		resp.Diagnostics.AddError(
+			"Incomplete Instance Credentials",
+			"An 'instance_url' was configured without 'client_id' and 'client_secret'. Please provide both, "+
+				"for example through the AIDBOX_CLIENT_ID and AIDBOX_CLIENT_SECRET environment variables.",
+		)
+		return
+	}
+
+	if data.InsecureSkipVerify.ValueBool() {
+		resp.Diagnostics.AddWarning(
+			"TLS Certificate Verification Disabled",
+			"'insecure_skip_verify' is enabled: the Aidbox server certificate will NOT be verified and "+
+				"credentials may be exposed to a man-in-the-middle. Never use this setting in production.",
+		)
+		tflog.Warn(ctx, "TLS certificate verification is disabled for Aidbox endpoints")
+	}
+
+	httpClient, err := aidboxclient.NewHTTPClient(aidboxclient.TransportConfig{
+		CACertPEM:          data.CACertPEM.ValueString(),
+		CACertFile:         data.CACertFile.ValueString(),
+		ClientCertPEM:      data.ClientCert.ValueString(),
+		ClientKeyPEM:       data.ClientKey.ValueString(),
+		InsecureSkipVerify: data.InsecureSkipVerify.ValueBool(),
+		ProxyURL:           data.ProxyURL.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid TLS or Proxy Configuration",
+			fmt.Sprintf("Unable to build the HTTP client for Aidbox: %s", err),
+		)
+		return
+	}
+
+	client := aidboxclient.NewClient(endpoint, token, httpClient)
+	client.InstanceURL = instanceURL
+	client.ClientID = clientID
+	client.ClientSecret = clientSecret
+
+	providerData := &ProviderData{
+		Endpoint:    endpoint,
+		Token:       token,
+		InstanceURL: instanceURL,
+		Client:      client,
+		HTTPClient:  httpClient,
+	}
+
+	if data.ValidateCreds.ValueBool() && token != "" {
+		user, err := client.WhoAmI(ctx)
+		if err != nil {
+			summary, detail := credentialsErrorDiagnostic("Portal", endpoint, err)
+			resp.Diagnostics.AddAttributeError(path.Root("token"), summary, detail)
+			return
+		}
+		tflog.Debug(ctx, "Validated Aidbox portal token", map[string]interface{}{"user_id": user.ID})
 	}
 
-	// Handle token; get from environment variable if not provided
-	if data.Token.IsNull() || data.Token.IsUnknown() || data.Token.ValueString() == "" {
-		tokenEnv := os.Getenv("AIDBOX_TOKEN")
-		if tokenEnv != "" {
-			data.Token = basetypes.NewStringValue(tokenEnv)
-		} else {
-			resp.Diagnostics.AddError(
-				"No Token Provided",
-				"Please provide a 'token' in the provider configuration or through the 'AIDBOX_TOKEN' environment variable.",
-			)
+	// The server version and capabilities are detected on first use by a
+	// resource that depends on them, unless the instance credentials are
+	// validated here.
+	if data.ValidateCreds.ValueBool() && instanceURL != "" {
+		serverInfo, err := client.GetServerInfo(ctx)
+		if err != nil {
+			summary, detail := credentialsErrorDiagnostic("Instance", instanceURL, err)
+			resp.Diagnostics.AddAttributeError(path.Root("instance_url"), summary, detail)
 			return
 		}
+		providerData.serverInfoOnce.Do(func() {
+			providerData.setServerInfo(ctx, serverInfo)
+		})
 	}
 
-	// Example client configuration for data sources and resources
-	client := http.DefaultClient
-	resp.DataSourceData = client
-	resp.ResourceData = &ProviderData{
-		Endpoint: data.Endpoint.ValueString(),
-		Token:    data.Token.ValueString(),
-		Client:   aidboxclient.NewClient(data.Endpoint.ValueString(), data.Token.ValueString()),
+	resp.DataSourceData = providerData
+	resp.ResourceData = providerData
+}
+
+// credentialsErrorDiagnostic tells apart unreachable endpoints from rejected
+// credentials so that practitioners know what to fix.
+func credentialsErrorDiagnostic(target, endpoint string, err error) (string, string) {
+	var netErr *aidboxclient.NetworkError
+	var apiErr *aidboxclient.APIError
+
+	switch {
+	case errors.As(err, &netErr):
+		return fmt.Sprintf("Aidbox %s Unreachable", target),
+			fmt.Sprintf("Unable to reach the Aidbox %s at %s (%s error). Please check the URL, DNS, TLS and proxy settings.\n\n%s",
+				strings.ToLower(target), endpoint, netErr.Kind, err)
+	case errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden):
+		return fmt.Sprintf("Invalid Aidbox %s Credentials", target),
+			fmt.Sprintf("The Aidbox %s at %s rejected the configured credentials (%s). Please check them.",
+				strings.ToLower(target), endpoint, apiErr.Status)
+	default:
+		return fmt.Sprintf("Unable to Validate Aidbox %s Credentials", target),
+			fmt.Sprintf("Validating the credentials against %s failed: %s", endpoint, err)
+	}
+}
+
+// stringValueOrEnv returns the configured value, falling back to the
+// environment variable when the attribute is not set.
+func stringValueOrEnv(value types.String, envVar string) string {
+	if !value.IsNull() && !value.IsUnknown() && value.ValueString() != "" {
+		return value.ValueString()
+	}
+	return os.Getenv(envVar)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
 	}
+	return ""
 }
 
 func (p *AidboxProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewExampleResource,
 		NewLicenseResource,
+		NewSearchParameterResource,
+		NewDBIndexResource,
+		NewSQLMigrationResource,
+		NewQueryResource,
+		NewSubscriptionTopicResource,
+		NewTopicDestinationResource,
+		NewSubscriptionResource,
+		NewFHIRPackageResource,
+		NewStructureDefinitionResource,
+		NewCodeSystemResource,
+		NewValueSetResource,
+		NewConceptMapResource,
+		NewAppResource,
+		NewOperationResource,
+		NewIdentityProviderResource,
+		NewTokenIntrospectorResource,
+		NewBoxResource,
+		NewPortalProjectResource,
+		NewPortalMemberResource,
+		NewHostedInstanceResource,
+		NewBulkImportResource,
+		NewJobResource,
+		NewNotificationTemplateResource,
+		NewEmailProviderResource,
+		NewMappingResource,
+		NewHl7v2ConfigResource,
 	}
 }
 
 func (p *AidboxProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewExampleDataSource,
+		NewDBIndexSuggestionsDataSource,
+		NewQueryResultDataSource,
+		NewValueSetExpansionDataSource,
+		NewBulkExportDataSource,
+		NewHl7v2ParseDataSource,
 	}
 }
 
@@ -4,10 +4,18 @@
 package provider
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -23,3 +31,122 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+// TestProviderSchemas checks the schemas of all resources and data sources
+// as Terraform would, e.g. for reserved attribute names.
+func TestProviderSchemas(t *testing.T) {
+	server, err := testAccProtoV6ProviderFactories["scaffolding"]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+}
+
+func TestConfigureUnknownCredentials(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("AIDBOX_INSTANCE_URL", "https://wrong.example.org")
+	t.Setenv("AIDBOX_CLIENT_ID", "env-client")
+	t.Setenv("AIDBOX_CLIENT_SECRET", "env-secret")
+	t.Setenv("AIDBOX_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	p := New("test")()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for _, unknown := range []string{"instance_url", "client_id", "client_secret", "token"} {
+		t.Run(unknown, func(t *testing.T) {
+			values := map[string]tftypes.Value{}
+			for name, attrType := range objectType.AttributeTypes {
+				values[name] = tftypes.NewValue(attrType, nil)
+			}
+			values["instance_url"] = tftypes.NewValue(tftypes.String, "https://box.example.org")
+			values[unknown] = tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+
+			req := provider.ConfigureRequest{
+				Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+			}
+			var resp provider.ConfigureResponse
+			p.Configure(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("expected no error, got %v", resp.Diagnostics)
+			}
+
+			data, ok := resp.ResourceData.(*ProviderData)
+			if !ok || !data.ConfigUnknown {
+				t.Fatalf("expected deferred provider data, got %#v", resp.ResourceData)
+			}
+			if data.InstanceURL != "" {
+				t.Errorf("expected no fallback to the environment, got instance URL %q", data.InstanceURL)
+			}
+
+			var diags diag.Diagnostics
+			if instanceProviderData(resp.ResourceData, &diags) == nil || diags.HasError() {
+				t.Errorf("expected resources to configure without error, got %v", diags)
+			}
+			if err := data.Client.GetResource(ctx, "Patient", "pt-1", nil); err == nil {
+				t.Error("expected requests to fail until the provider is configured")
+			}
+		})
+	}
+}
+
+func TestConfigureProbesServerLazily(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AIDBOX_CREDENTIALS_FILE", "")
+	t.Setenv("AIDBOX_TOKEN", "")
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/$version" {
+			_, _ = w.Write([]byte(`{"version": "2405.0", "channel": "stable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	p := New("test")()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["instance_url"] = tftypes.NewValue(tftypes.String, server.URL)
+	values["client_id"] = tftypes.NewValue(tftypes.String, "client")
+	values["client_secret"] = tftypes.NewValue(tftypes.String, "secret")
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected no error, got %v", resp.Diagnostics)
+	}
+	if requests != 0 {
+		t.Errorf("expected no requests without validate_credentials, got %d", requests)
+	}
+
+	data := resp.ResourceData.(*ProviderData)
+	info := data.ServerInfo(ctx)
+	if info == nil || info.Version.Version != "2405.0" {
+		t.Fatalf("expected the detected server version, got %+v", info)
+	}
+	probed := requests
+	if data.ServerInfo(ctx) != info || requests != probed {
+		t.Errorf("expected the server info to be cached, got %d more requests", requests-probed)
+	}
+}
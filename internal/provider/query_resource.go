@@ -0,0 +1,284 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &QueryResource{}
+var _ resource.ResourceWithImportState = &QueryResource{}
+
+func NewQueryResource() resource.Resource {
+	return &QueryResource{}
+}
+
+// QueryResource defines the resource implementation.
+type QueryResource struct {
+	client Client
+}
+
+// QueryResourceModel describes the resource data model.
+type QueryResourceModel struct {
+	ID         types.String               `tfsdk:"id"`
+	Query      types.String               `tfsdk:"query"`
+	CountQuery types.String               `tfsdk:"count_query"`
+	Params     map[string]QueryParamModel `tfsdk:"params"`
+}
+
+// QueryParamModel describes a parameter of the query.
+type QueryParamModel struct {
+	Type     types.String `tfsdk:"type"`
+	Default  types.String `tfsdk:"default"`
+	Required types.Bool   `tfsdk:"required"`
+	Format   types.String `tfsdk:"format"`
+}
+
+func (r *QueryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_query"
+}
+
+func (r *QueryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an `AidboxQuery`, a parameterized SQL endpoint exposed at `/$query/<id>`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Query name, used in `/$query/<id>`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"query": schema.StringAttribute{
+				MarkdownDescription: "SQL query. Parameters are referenced as `{{params.<name>}}`",
+				Required:            true,
+			},
+			"count_query": schema.StringAttribute{
+				MarkdownDescription: "SQL query returning the total number of rows, exposed as `total`",
+				Optional:            true,
+			},
+			"params": schema.MapNestedAttribute{
+				MarkdownDescription: "Query parameters, keyed by name",
+				Optional:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Parameter type: `string`, `integer`, `number` or `boolean`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.OneOf("string", "integer", "number", "boolean"),
+							},
+						},
+						"default": schema.StringAttribute{
+							MarkdownDescription: "Default value, converted to `type`",
+							Optional:            true,
+						},
+						"required": schema.BoolAttribute{
+							MarkdownDescription: "Whether the parameter must be provided",
+							Optional:            true,
+						},
+						"format": schema.StringAttribute{
+							MarkdownDescription: "Format of string parameters, e.g. `date`",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *QueryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *QueryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model QueryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *QueryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model QueryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var query aidboxclient.AidboxQuery
+	err := r.client.GetResource(ctx, "AidboxQuery", model.ID.ValueString(), &query)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "AidboxQuery not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch AidboxQuery", fmt.Sprintf("Unable to fetch AidboxQuery %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapQueryToModel(&model, query)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *QueryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model QueryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *QueryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model QueryResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "AidboxQuery", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete AidboxQuery",
+			fmt.Sprintf("Error while trying to delete the AidboxQuery with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *QueryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *QueryResource) save(ctx context.Context, model QueryResourceModel, diags *diag.Diagnostics) {
+	query := aidboxclient.AidboxQuery{
+		ResourceType: "AidboxQuery",
+		ID:           model.ID.ValueString(),
+		Query:        model.Query.ValueString(),
+		CountQuery:   model.CountQuery.ValueString(),
+	}
+
+	for name, p := range model.Params {
+		if query.Params == nil {
+			query.Params = map[string]aidboxclient.AidboxQueryParam{}
+		}
+		param := aidboxclient.AidboxQueryParam{
+			Type:       p.Type.ValueString(),
+			IsRequired: p.Required.ValueBoolPointer(),
+			Format:     p.Format.ValueString(),
+		}
+		if !p.Default.IsNull() {
+			value, err := parseScalar(p.Type.ValueString(), p.Default.ValueString())
+			if err != nil {
+				diags.AddAttributeError(
+					path.Root("params").AtMapKey(name).AtName("default"),
+					"Invalid Default Value",
+					fmt.Sprintf("Default %q is not a valid %s: %s", p.Default.ValueString(), p.Type.ValueString(), err),
+				)
+				continue
+			}
+			param.Default = value
+		}
+		query.Params[name] = param
+	}
+	if diags.HasError() {
+		return
+	}
+
+	if err := r.client.PutResource(ctx, "AidboxQuery", query.ID, query, nil); err != nil {
+		diags.AddError("Failed to Save AidboxQuery", fmt.Sprintf("Unable to save AidboxQuery %s: %s", query.ID, err))
+	}
+}
+
+// mapQueryToModel sets the model from the stored query. An empty params map
+// and defaults that only differ in formatting, e.g. "1.0" and "1", are kept
+// as configured.
+func mapQueryToModel(model *QueryResourceModel, query aidboxclient.AidboxQuery) {
+	model.Query = types.StringValue(query.Query)
+	model.CountQuery = optionalStringValue(query.CountQuery)
+
+	prior := model.Params
+	model.Params = nil
+	if prior != nil || len(query.Params) > 0 {
+		model.Params = make(map[string]QueryParamModel, len(query.Params))
+	}
+	for name, p := range query.Params {
+		param := QueryParamModel{
+			Type:     types.StringValue(p.Type),
+			Default:  types.StringNull(),
+			Required: types.BoolPointerValue(p.IsRequired),
+			Format:   optionalStringValue(p.Format),
+		}
+		if p.Default != nil {
+			param.Default = types.StringValue(formatScalar(p.Default))
+			if priorDefault := prior[name].Default; !priorDefault.IsNull() && !priorDefault.IsUnknown() {
+				value, err := parseScalar(p.Type, priorDefault.ValueString())
+				if err == nil && formatScalar(value) == formatScalar(p.Default) {
+					param.Default = priorDefault
+				}
+			}
+		}
+		model.Params[name] = param
+	}
+}
+
+// parseScalar converts value to the JSON type named by typeName.
+func parseScalar(typeName, value string) (interface{}, error) {
+	switch typeName {
+	case "integer":
+		return strconv.ParseInt(value, 10, 64)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	default:
+		return value, nil
+	}
+}
+
+// formatScalar renders a decoded JSON scalar as a string.
+func formatScalar(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
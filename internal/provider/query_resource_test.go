@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"encoding/json"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestQueryParamDefaultRoundTrip(t *testing.T) {
+	cases := map[string]string{
+		"string":  "1980",
+		"integer": "100",
+		"number":  "0.5",
+		"boolean": "true",
+	}
+
+	for typeName, value := range cases {
+		parsed, err := parseScalar(typeName, value)
+		if err != nil {
+			t.Fatalf("%s: %s", typeName, err)
+		}
+
+		// Defaults come back from Aidbox as decoded JSON.
+		raw, err := json.Marshal(parsed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded interface{}
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if got := formatScalar(decoded); got != value {
+			t.Errorf("%s: expected %q, got %q", typeName, value, got)
+		}
+	}
+
+	if _, err := parseScalar("integer", "ten"); err == nil {
+		t.Error("expected an error for an invalid integer")
+	}
+}
+
+func TestFormatQueryValue(t *testing.T) {
+	var row map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"id":"pt-1","count":3,"name":[{"family":"Doe"}]}`), &row); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"id":    "pt-1",
+		"count": "3",
+		"name":  `[{"family":"Doe"}]`,
+	}
+	for k, v := range expected {
+		if got := formatQueryValue(row[k]); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+}
+
+func TestMapQueryToModel(t *testing.T) {
+	var query aidboxclient.AidboxQuery
+	if err := json.Unmarshal([]byte(`{
+		"query": "SELECT 1",
+		"params": {
+			"limit": {"type": "integer", "default": 10},
+			"ratio": {"type": "number", "default": 1},
+			"since": {"type": "string", "default": "1980"}
+		}
+	}`), &query); err != nil {
+		t.Fatal(err)
+	}
+
+	param := func(typeName, value string) QueryParamModel {
+		return QueryParamModel{
+			Type:     types.StringValue(typeName),
+			Default:  types.StringValue(value),
+			Required: types.BoolNull(),
+			Format:   types.StringNull(),
+		}
+	}
+	model := QueryResourceModel{Params: map[string]QueryParamModel{
+		"limit": param("integer", "010"),
+		"ratio": param("number", "1.0"),
+		"since": param("string", "1990"),
+	}}
+	mapQueryToModel(&model, query)
+
+	expected := map[string]string{
+		"limit": "010",
+		"ratio": "1.0",
+		"since": "1980",
+	}
+	for name, want := range expected {
+		if got := model.Params[name].Default.ValueString(); got != want {
+			t.Errorf("%s: expected default %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestMapQueryToModelEmptyParams(t *testing.T) {
+	query := aidboxclient.AidboxQuery{Query: "SELECT 1"}
+
+	model := QueryResourceModel{Params: map[string]QueryParamModel{}}
+	mapQueryToModel(&model, query)
+	if model.Params == nil || len(model.Params) != 0 {
+		t.Errorf("expected an empty params map to be kept, got %#v", model.Params)
+	}
+
+	model = QueryResourceModel{}
+	mapQueryToModel(&model, query)
+	if model.Params != nil {
+		t.Errorf("expected null params to be kept, got %#v", model.Params)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &QueryResultDataSource{}
+
+func NewQueryResultDataSource() datasource.DataSource {
+	return &QueryResultDataSource{}
+}
+
+// QueryResultDataSource defines the data source implementation.
+type QueryResultDataSource struct {
+	client Client
+}
+
+// QueryResultDataSourceModel describes the data source data model.
+type QueryResultDataSourceModel struct {
+	Name   types.String        `tfsdk:"name"`
+	Params map[string]string   `tfsdk:"params"`
+	Rows   []map[string]string `tfsdk:"rows"`
+	Total  types.Int64         `tfsdk:"total"`
+}
+
+func (d *QueryResultDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_query_result"
+}
+
+func (d *QueryResultDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Executes an `AidboxQuery` through `/$query/<name>` and returns its rows",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Query name",
+				Required:            true,
+			},
+			"params": schema.MapAttribute{
+				MarkdownDescription: "Query parameters",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"rows": schema.ListAttribute{
+				MarkdownDescription: "Result rows. Values that are not strings are JSON-encoded",
+				ElementType:         types.MapType{ElemType: types.StringType},
+				Computed:            true,
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "Total number of rows, when the query defines a `count_query`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *QueryResultDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *QueryResultDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model QueryResultDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	result, err := d.client.RunQuery(ctx, model.Name.ValueString(), model.Params)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Run Query", fmt.Sprintf("Unable to run query %s: %s", model.Name.ValueString(), err))
+		return
+	}
+
+	model.Rows = make([]map[string]string, 0, len(result.Data))
+	for _, row := range result.Data {
+		values := make(map[string]string, len(row))
+		for k, v := range row {
+			values[k] = formatQueryValue(v)
+		}
+		model.Rows = append(model.Rows, values)
+	}
+	model.Total = types.Int64PointerValue(result.Total)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+// formatQueryValue renders a column value as a string, JSON-encoding values
+// that are not strings.
+func formatQueryValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,448 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SearchParameterResource{}
+var _ resource.ResourceWithImportState = &SearchParameterResource{}
+var _ resource.ResourceWithValidateConfig = &SearchParameterResource{}
+
+const (
+	searchParameterFormatFHIR   = "fhir"
+	searchParameterFormatAidbox = "aidbox"
+
+	searchParameterReindexPollInterval    = 2 * time.Second
+	searchParameterReindexMaxPollInterval = 30 * time.Second
+	searchParameterReindexTimeout         = 20 * time.Minute
+)
+
+var searchParameterTypes = []string{"number", "date", "string", "token", "reference", "composite", "quantity", "uri", "special"}
+
+var resourceTypeRegexp = regexp.MustCompile(`^[A-Z][A-Za-z0-9]*$`)
+
+func NewSearchParameterResource() resource.Resource {
+	return &SearchParameterResource{}
+}
+
+// SearchParameterResource defines the resource implementation.
+type SearchParameterResource struct {
+	client Client
+}
+
+// SearchParameterResourceModel describes the resource data model.
+type SearchParameterResourceModel struct {
+	ID          types.String   `tfsdk:"id"`
+	Format      types.String   `tfsdk:"format"`
+	Name        types.String   `tfsdk:"name"`
+	Type        types.String   `tfsdk:"type"`
+	Base        types.List     `tfsdk:"base"`
+	Expression  types.String   `tfsdk:"expression"`
+	Paths       types.List     `tfsdk:"paths"`
+	URL         types.String   `tfsdk:"url"`
+	Status      types.String   `tfsdk:"status"`
+	Description types.String   `tfsdk:"description"`
+	Reindex     types.Bool     `tfsdk:"reindex"`
+	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *SearchParameterResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_search_parameter"
+}
+
+func (r *SearchParameterResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a custom SearchParameter of an Aidbox instance, either as a FHIR `SearchParameter` " +
+			"with a FHIRPath `expression` or as an Aidbox-native `SearchParameter` with element `paths`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "SearchParameter resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "`fhir` for a FHIR SearchParameter, `aidbox` for an Aidbox-native SearchParameter. Defaults to `fhir`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(searchParameterFormatFHIR),
+				Validators: []validator.String{
+					stringvalidator.OneOf(searchParameterFormatFHIR, searchParameterFormatAidbox),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Name used in search requests, e.g. `birth-place` in `?birth-place=Paris`",
+				Required:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Search parameter type: one of `" + strings.Join(searchParameterTypes, "`, `") + "`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(searchParameterTypes...),
+				},
+			},
+			"base": schema.ListAttribute{
+				MarkdownDescription: "Resource types the parameter applies to. Aidbox-native parameters support exactly one",
+				ElementType:         types.StringType,
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(stringvalidator.RegexMatches(resourceTypeRegexp, "must be a resource type, e.g. Patient")),
+				},
+			},
+			"expression": schema.StringAttribute{
+				MarkdownDescription: "FHIRPath expression. Required when `format` is `fhir`",
+				Optional:            true,
+			},
+			"paths": schema.ListAttribute{
+				MarkdownDescription: "Dot-separated element paths, e.g. `name.given` or `identifier.0.value`. Required when `format` is `aidbox`",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL. Required when `format` is `fhir`",
+				Optional:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Publication status of a FHIR SearchParameter. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("draft", "active", "retired", "unknown"),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"reindex": schema.BoolAttribute{
+				MarkdownDescription: "Rebuild the search index of the `base` resource types after the parameter is created or updated. Defaults to `false`",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create:            true,
+				Update:            true,
+				CreateDescription: "How long to wait for the reindex. Defaults to `20m`",
+				UpdateDescription: "How long to wait for the reindex. Defaults to `20m`",
+			}),
+		},
+	}
+}
+
+func (r *SearchParameterResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var format, expression, url types.String
+	var base, paths types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("format"), &format)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expression"), &expression)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("url"), &url)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("base"), &base)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("paths"), &paths)...)
+	if resp.Diagnostics.HasError() || format.IsUnknown() {
+		return
+	}
+
+	if format.ValueString() == searchParameterFormatAidbox {
+		if paths.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("paths"), "Missing Attribute", "'paths' is required when 'format' is \"aidbox\".")
+		}
+		if !expression.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("expression"), "Invalid Attribute", "'expression' is only supported when 'format' is \"fhir\"; use 'paths' instead.")
+		}
+		if !base.IsUnknown() && len(base.Elements()) > 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("base"), "Invalid Attribute", "Aidbox-native search parameters apply to exactly one resource type.")
+		}
+		return
+	}
+
+	if expression.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("expression"), "Missing Attribute", "'expression' is required when 'format' is \"fhir\".")
+	}
+	if url.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("url"), "Missing Attribute", "'url' is required when 'format' is \"fhir\".")
+	}
+	if !paths.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("paths"), "Invalid Attribute", "'paths' is only supported when 'format' is \"aidbox\"; use 'expression' instead.")
+	}
+}
+
+func (r *SearchParameterResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *SearchParameterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model SearchParameterResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Create(ctx, searchParameterReindexTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SearchParameterResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model SearchParameterResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	if model.Format.ValueString() == searchParameterFormatAidbox {
+		var sp aidboxclient.AidboxSearchParameter
+		err = r.client.GetResource(ctx, "SearchParameter", model.ID.ValueString(), &sp)
+		if err == nil {
+			mapAidboxSearchParameterToModel(&model, sp)
+		}
+	} else {
+		var sp aidboxclient.SearchParameter
+		err = r.client.GetResource(ctx, "fhir/SearchParameter", model.ID.ValueString(), &sp)
+		if err == nil {
+			mapFHIRSearchParameterToModel(&model, sp)
+		}
+	}
+
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "SearchParameter not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch SearchParameter", fmt.Sprintf("Unable to fetch SearchParameter %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SearchParameterResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model SearchParameterResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	timeout, diags := model.Timeouts.Update(ctx, searchParameterReindexTimeout)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, timeout, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SearchParameterResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model SearchParameterResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	collection := "fhir/SearchParameter"
+	if model.Format.ValueString() == searchParameterFormatAidbox {
+		collection = "SearchParameter"
+	}
+
+	if err := r.client.DeleteResource(ctx, collection, model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete SearchParameter",
+			fmt.Sprintf("Error while trying to delete the SearchParameter with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *SearchParameterResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import IDs are either "<id>" for FHIR search parameters or "aidbox/<id>"
+	// for Aidbox-native ones.
+	format, id, ok := strings.Cut(req.ID, "/")
+	if !ok {
+		format, id = searchParameterFormatFHIR, req.ID
+	}
+	if format != searchParameterFormatFHIR && format != searchParameterFormatAidbox {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected \"<id>\" or \"aidbox/<id>\", got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("format"), format)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("reindex"), false)...)
+}
+
+// save creates or replaces the search parameter and optionally reindexes
+// its base resource types, waiting at most timeout for the reindex.
+func (r *SearchParameterResource) save(ctx context.Context, model *SearchParameterResourceModel, timeout time.Duration, diags *diag.Diagnostics) {
+	id := model.ID.ValueString()
+
+	var base, paths []string
+	diags.Append(model.Base.ElementsAs(ctx, &base, false)...)
+	diags.Append(model.Paths.ElementsAs(ctx, &paths, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	var err error
+	if model.Format.ValueString() == searchParameterFormatAidbox {
+		sp := aidboxclient.AidboxSearchParameter{
+			ResourceType: "SearchParameter",
+			ID:           id,
+			Name:         model.Name.ValueString(),
+			Description:  model.Description.ValueString(),
+			Type:         model.Type.ValueString(),
+			Resource:     aidboxclient.Reference{ID: base[0], ResourceType: "Entity"},
+		}
+		for _, p := range paths {
+			sp.Expression = append(sp.Expression, parseElementPath(p))
+		}
+		err = r.client.PutResource(ctx, "SearchParameter", id, sp, nil)
+	} else {
+		sp := aidboxclient.SearchParameter{
+			ResourceType: "SearchParameter",
+			ID:           id,
+			URL:          model.URL.ValueString(),
+			Name:         model.Name.ValueString(),
+			Code:         model.Name.ValueString(),
+			Status:       model.Status.ValueString(),
+			Description:  model.Description.ValueString(),
+			Type:         model.Type.ValueString(),
+			Expression:   model.Expression.ValueString(),
+			Base:         base,
+		}
+		err = r.client.PutResource(ctx, "fhir/SearchParameter", id, sp, nil)
+	}
+	if err != nil {
+		diags.AddError("Failed to Save SearchParameter", fmt.Sprintf("Unable to save SearchParameter %s: %s", id, err))
+		return
+	}
+
+	if !model.Reindex.ValueBool() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, b := range base {
+		tflog.Info(ctx, "Reindexing resource type", map[string]interface{}{"resource_type": b})
+		statusURL, err := r.client.Reindex(ctx, b)
+		if err == nil && statusURL != "" {
+			_, err = r.client.WaitAsync(ctx, statusURL, aidboxclient.PollOptions{
+				Description: "reindex of " + b,
+				Interval:    searchParameterReindexPollInterval,
+				Backoff:     1.5,
+				MaxInterval: searchParameterReindexMaxPollInterval,
+			})
+		}
+		if err != nil {
+			diags.AddWarning(
+				"Failed to Rebuild Search Index",
+				fmt.Sprintf("SearchParameter %s was saved, but reindexing %s failed: %s", id, b, err),
+			)
+		}
+	}
+}
+
+func mapFHIRSearchParameterToModel(model *SearchParameterResourceModel, sp aidboxclient.SearchParameter) {
+	model.Format = types.StringValue(searchParameterFormatFHIR)
+	model.Name = types.StringValue(sp.Code)
+	model.Type = types.StringValue(sp.Type)
+	model.Expression = types.StringValue(sp.Expression)
+	model.URL = types.StringValue(sp.URL)
+	model.Status = types.StringValue(sp.Status)
+	model.Description = optionalStringValue(sp.Description)
+	model.Paths = types.ListNull(types.StringType)
+	model.Base = stringListValue(sp.Base)
+}
+
+func mapAidboxSearchParameterToModel(model *SearchParameterResourceModel, sp aidboxclient.AidboxSearchParameter) {
+	model.Format = types.StringValue(searchParameterFormatAidbox)
+	model.Name = types.StringValue(sp.Name)
+	model.Type = types.StringValue(sp.Type)
+	model.Description = optionalStringValue(sp.Description)
+	model.Base = stringListValue([]string{sp.Resource.ID})
+	paths := make([]string, 0, len(sp.Expression))
+	for _, p := range sp.Expression {
+		paths = append(paths, formatElementPath(p))
+	}
+	model.Paths = stringListValue(paths)
+}
+
+// parseElementPath turns "identifier.0.value" into ["identifier", 0, "value"].
+func parseElementPath(p string) []interface{} {
+	segments := strings.Split(p, ".")
+	result := make([]interface{}, 0, len(segments))
+	for _, s := range segments {
+		if n, err := strconv.Atoi(s); err == nil {
+			result = append(result, n)
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// formatElementPath is the inverse of parseElementPath.
+func formatElementPath(segments []interface{}) string {
+	parts := make([]string, 0, len(segments))
+	for _, s := range segments {
+		switch v := s.(type) {
+		case float64:
+			parts = append(parts, strconv.Itoa(int(v)))
+		default:
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestElementPath(t *testing.T) {
+	parsed := parseElementPath("identifier.0.value")
+	expected := []interface{}{"identifier", 0, "value"}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Fatalf("expected %v, got %v", expected, parsed)
+	}
+
+	// Round trip through JSON, as numbers come back as float64.
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := formatElementPath(decoded); got != "identifier.0.value" {
+		t.Errorf("expected %q, got %q", "identifier.0.value", got)
+	}
+}
+
+func TestSearchParameterModelUnknownBase(t *testing.T) {
+	ctx := context.Background()
+	r := &SearchParameterResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "patient-mrn")
+	values["base"] = tftypes.NewValue(objectType.AttributeTypes["base"], tftypes.UnknownValue)
+	values["paths"] = tftypes.NewValue(objectType.AttributeTypes["paths"], tftypes.UnknownValue)
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)}
+
+	var model SearchParameterResourceModel
+	if diags := plan.Get(ctx, &model); diags.HasError() {
+		t.Fatalf("expected an unknown base to decode, got %v", diags)
+	}
+	if !model.Base.IsUnknown() || !model.Paths.IsUnknown() {
+		t.Errorf("expected unknown lists, got %s and %s", model.Base, model.Paths)
+	}
+}
+
+func TestMapSearchParameterToModel(t *testing.T) {
+	var model SearchParameterResourceModel
+	mapFHIRSearchParameterToModel(&model, aidboxclient.SearchParameter{
+		Code:       "mrn",
+		Type:       "token",
+		Base:       []string{"Patient", "Person"},
+		Expression: "Patient.identifier",
+	})
+	expected := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Patient"), types.StringValue("Person")})
+	if !model.Base.Equal(expected) || !model.Paths.IsNull() {
+		t.Errorf("unexpected base %s and paths %s", model.Base, model.Paths)
+	}
+
+	mapAidboxSearchParameterToModel(&model, aidboxclient.AidboxSearchParameter{
+		Name:       "mrn",
+		Type:       "token",
+		Resource:   aidboxclient.Reference{ID: "Patient", ResourceType: "Entity"},
+		Expression: [][]interface{}{{"identifier", float64(0), "value"}},
+	})
+	expected = types.ListValueMust(types.StringType, []attr.Value{types.StringValue("Patient")})
+	paths := types.ListValueMust(types.StringType, []attr.Value{types.StringValue("identifier.0.value")})
+	if !model.Base.Equal(expected) || !model.Paths.Equal(paths) {
+		t.Errorf("unexpected base %s and paths %s", model.Base, model.Paths)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// requireServerFeature returns an error diagnostic when the connected
+// instance is known not to provide feature. When the server could not be
+// probed, the feature is assumed to be available and the API reports errors.
+func requireServerFeature(info *aidboxclient.ServerInfo, feature aidboxclient.ServerFeature, typeName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if info == nil {
+		return diags
+	}
+
+	if err := info.Require(feature); err != nil {
+		diags.AddError(
+			"Unsupported by the Connected Aidbox Instance",
+			fmt.Sprintf("%s cannot be used with this Aidbox instance: %s. Please upgrade Aidbox or enable the feature.", typeName, err),
+		)
+	}
+
+	return diags
+}
+
+// requireServerFeatureOnCreate checks feature when a resource is about to be
+// created. Existing resources are not checked so that they can still be
+// refreshed and destroyed after a downgrade or a failed probe.
+func requireServerFeatureOnCreate(ctx context.Context, req resource.ModifyPlanRequest, serverInfo func(context.Context) *aidboxclient.ServerInfo, feature aidboxclient.ServerFeature, typeName string) diag.Diagnostics {
+	if !req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || serverInfo == nil {
+		return nil
+	}
+	return requireServerFeature(serverInfo(ctx), feature, typeName)
+}
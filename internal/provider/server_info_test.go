@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRequireServerFeature(t *testing.T) {
+	feature := aidboxclient.ServerFeature{Name: "topics", MinVersion: "2402", ResourceType: "AidboxSubscriptionTopic"}
+
+	testCases := map[string]struct {
+		info      *aidboxclient.ServerInfo
+		expectErr bool
+	}{
+		"unknown server": {
+			info: nil,
+		},
+		"supported": {
+			info: &aidboxclient.ServerInfo{
+				Version:       aidboxclient.ServerVersion{Version: "2405.0"},
+				ResourceTypes: map[string]bool{"AidboxSubscriptionTopic": true},
+			},
+		},
+		"too old": {
+			info:      &aidboxclient.ServerInfo{Version: aidboxclient.ServerVersion{Version: "2309.2"}},
+			expectErr: true,
+		},
+		"missing resource type": {
+			info: &aidboxclient.ServerInfo{
+				Version:       aidboxclient.ServerVersion{Version: "2405.0"},
+				ResourceTypes: map[string]bool{"Patient": true},
+			},
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := requireServerFeature(tc.info, feature, "aidbox_test")
+			if diags.HasError() != tc.expectErr {
+				t.Errorf("expected error: %t, got diagnostics: %v", tc.expectErr, diags)
+			}
+		})
+	}
+}
+
+func TestRequireServerFeatureOnCreate(t *testing.T) {
+	ctx := context.Background()
+	r := &SubscriptionTopicResource{
+		serverInfo: func(context.Context) *aidboxclient.ServerInfo {
+			return &aidboxclient.ServerInfo{ResourceTypes: map[string]bool{"Patient": true}}
+		},
+	}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := map[string]tftypes.Value{}
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	values["id"] = tftypes.NewValue(tftypes.String, "patient-changes")
+	existing := tftypes.NewValue(objectType, values)
+	null := tftypes.NewValue(objectType, nil)
+
+	testCases := map[string]struct {
+		state     tftypes.Value
+		plan      tftypes.Value
+		expectErr bool
+	}{
+		"create":  {state: null, plan: existing, expectErr: true},
+		"update":  {state: existing, plan: existing},
+		"destroy": {state: existing, plan: null},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: tc.state},
+				Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: tc.plan},
+			}
+			resp := resource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &resp)
+			if resp.Diagnostics.HasError() != tc.expectErr {
+				t.Errorf("expected error: %t, got diagnostics: %v", tc.expectErr, resp.Diagnostics)
+			}
+		})
+	}
+
+	// Unconfigured resources are not checked.
+	req := resource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: null},
+		Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: existing},
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	(&SubscriptionTopicResource{}).ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Errorf("expected no error before the provider is configured, got %v", resp.Diagnostics)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SQLMigrationResource{}
+var _ resource.ResourceWithModifyPlan = &SQLMigrationResource{}
+var _ resource.ResourceWithImportState = &SQLMigrationResource{}
+
+func NewSQLMigrationResource() resource.Resource {
+	return &SQLMigrationResource{}
+}
+
+// SQLMigrationResource defines the resource implementation.
+type SQLMigrationResource struct {
+	client Client
+}
+
+// SQLMigrationResourceModel describes the resource data model.
+type SQLMigrationResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	Up        types.String `tfsdk:"up"`
+	Down      types.String `tfsdk:"down"`
+	Checksum  types.String `tfsdk:"checksum"`
+	AppliedAt types.String `tfsdk:"applied_at"`
+}
+
+func (r *SQLMigrationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sql_migration"
+}
+
+func (r *SQLMigrationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Applies a versioned SQL migration to the database of an Aidbox instance through the `/$sql` endpoint. " +
+			"Applied migrations are recorded in the `public.terraform_sql_migration` table. Order migrations with `depends_on`; " +
+			"to change an applied migration, add a new one or give it a new `id`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Migration identifier, e.g. `0001_create_reporting_views`",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"up": schema.StringAttribute{
+				MarkdownDescription: "SQL applied when the migration is created. Cannot be changed once applied",
+				Required:            true,
+			},
+			"down": schema.StringAttribute{
+				MarkdownDescription: "SQL applied when the migration is destroyed",
+				Optional:            true,
+			},
+			"checksum": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 checksum of the applied `up` script",
+				Computed:            true,
+			},
+			"applied_at": schema.StringAttribute{
+				MarkdownDescription: "Time the migration was applied",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *SQLMigrationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+// ModifyPlan computes the checksum of the planned script and rejects changes
+// to the script of an applied migration.
+func (r *SQLMigrationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Up.IsUnknown() {
+		return
+	}
+
+	checksum := aidboxclient.SQLChecksum(plan.Up.ValueString())
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("checksum"), checksum)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	var state SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A new id replaces the migration, which reverts and re-applies it.
+	if !plan.ID.Equal(state.ID) {
+		return
+	}
+
+	if state.Checksum.ValueString() != checksum {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("up"),
+			"Applied Migration Changed",
+			fmt.Sprintf("Migration %s was applied with checksum %s, but the configured script has checksum %s. "+
+				"Applied migrations cannot be modified: add a new migration, or change the migration id to revert and re-apply it.",
+				state.ID.ValueString(), state.Checksum.ValueString(), checksum),
+		)
+	}
+}
+
+func (r *SQLMigrationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Applying SQL migration", map[string]interface{}{"id": model.ID.ValueString()})
+	migration, err := r.client.ApplySQLMigration(ctx, model.ID.ValueString(), model.Up.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Apply Migration", err.Error())
+		return
+	}
+
+	model.Checksum = types.StringValue(migration.Checksum)
+	model.AppliedAt = types.StringValue(migration.AppliedAt)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SQLMigrationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	migration, err := r.client.GetSQLMigration(ctx, model.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Read Migration", fmt.Sprintf("Unable to read migration %s: %s", model.ID.ValueString(), err))
+		return
+	}
+	if migration == nil {
+		tflog.Warn(ctx, "SQL migration not recorded as applied, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	model.Checksum = types.StringValue(migration.Checksum)
+	model.AppliedAt = types.StringValue(migration.AppliedAt)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SQLMigrationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// ModifyPlan only lets "down" change in place, which is stored in state.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SQLMigrationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model SQLMigrationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Info(ctx, "Reverting SQL migration", map[string]interface{}{"id": model.ID.ValueString()})
+	if err := r.client.RevertSQLMigration(ctx, model.ID.ValueString(), model.Down.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to Revert Migration", err.Error())
+	}
+}
+
+func (r *SQLMigrationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"terraform-provider-aidbox/internal/aidboxclient"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestSQLMigrationModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &SQLMigrationResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	value := func(id, up, checksum interface{}) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"id":         tftypes.NewValue(tftypes.String, id),
+			"up":         tftypes.NewValue(tftypes.String, up),
+			"down":       tftypes.NewValue(tftypes.String, nil),
+			"checksum":   tftypes.NewValue(tftypes.String, checksum),
+			"applied_at": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		})
+	}
+
+	oldUp := "CREATE VIEW v AS SELECT 1"
+	newUp := "CREATE VIEW v AS SELECT 2"
+	applied := value("0001", oldUp, aidboxclient.SQLChecksum(oldUp))
+
+	testCases := map[string]struct {
+		state    tftypes.Value
+		plan     tftypes.Value
+		wantErr  bool
+		checksum string
+	}{
+		"create": {
+			state:    tftypes.NewValue(objectType, nil),
+			plan:     value("0001", newUp, tftypes.UnknownValue),
+			checksum: aidboxclient.SQLChecksum(newUp),
+		},
+		"unchanged": {
+			state:    applied,
+			plan:     value("0001", oldUp, tftypes.UnknownValue),
+			checksum: aidboxclient.SQLChecksum(oldUp),
+		},
+		"changed checksum": {
+			state:   applied,
+			plan:    value("0001", newUp, tftypes.UnknownValue),
+			wantErr: true,
+		},
+		"new id": {
+			state:    applied,
+			plan:     value("0002", newUp, tftypes.UnknownValue),
+			checksum: aidboxclient.SQLChecksum(newUp),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: tc.state},
+				Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: tc.plan},
+			}
+			resp := resource.ModifyPlanResponse{
+				Plan: tfsdk.Plan{Schema: schemaResp.Schema, Raw: tc.plan},
+			}
+			r.ModifyPlan(ctx, req, &resp)
+
+			if got := resp.Diagnostics.HasError(); got != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, resp.Diagnostics)
+			}
+			if tc.wantErr {
+				return
+			}
+
+			var plan SQLMigrationResourceModel
+			resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+			if plan.Checksum.ValueString() != tc.checksum {
+				t.Errorf("expected checksum %s, got %s", tc.checksum, plan.Checksum)
+			}
+		})
+	}
+}
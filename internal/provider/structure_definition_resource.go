@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &StructureDefinitionResource{}
+var _ resource.ResourceWithImportState = &StructureDefinitionResource{}
+
+const (
+	structureDefinitionFormatStructureDefinition = "structure_definition"
+	structureDefinitionFormatFHIRSchema          = "fhir_schema"
+)
+
+func NewStructureDefinitionResource() resource.Resource {
+	return &StructureDefinitionResource{}
+}
+
+// StructureDefinitionResource defines the resource implementation.
+type StructureDefinitionResource struct {
+	client     Client
+	serverInfo func(context.Context) *aidboxclient.ServerInfo
+}
+
+// StructureDefinitionResourceModel describes the resource data model.
+type StructureDefinitionResourceModel struct {
+	ID      types.String `tfsdk:"id"`
+	Format  types.String `tfsdk:"format"`
+	Content types.String `tfsdk:"content"`
+	URL     types.String `tfsdk:"url"`
+}
+
+func (r *StructureDefinitionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_structure_definition"
+}
+
+func (r *StructureDefinitionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a custom profile of an Aidbox instance, given as a FHIR `StructureDefinition` or as a `FHIRSchema`. " +
+			"Validation errors reported by Aidbox are mapped to the offending elements of `content`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "`structure_definition` for a FHIR StructureDefinition, `fhir_schema` for a FHIR Schema. Defaults to `structure_definition`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(structureDefinitionFormatStructureDefinition),
+				Validators: []validator.String{
+					stringvalidator.OneOf(structureDefinitionFormatStructureDefinition, structureDefinitionFormatFHIRSchema),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Resource as a JSON document, e.g. `file(\"profiles/us-patient.json\")`. `resourceType` and `id` are set by the provider",
+				Required:            true,
+				Validators: []validator.String{
+					jsonStringValidator{},
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL of the profile, read from `content`",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *StructureDefinitionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+	r.serverInfo = data.ServerInfo
+}
+
+func (r *StructureDefinitionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model StructureDefinitionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *StructureDefinitionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model StructureDefinitionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resourceType := structureDefinitionResourceType(model.Format.ValueString())
+
+	var raw json.RawMessage
+	err := r.client.GetResource(ctx, "fhir/"+resourceType, model.ID.ValueString(), &raw)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, resourceType+" not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch "+resourceType, fmt.Sprintf("Unable to fetch %s %s: %s", resourceType, model.ID.ValueString(), err))
+		return
+	}
+
+	content, err := jsonObjectValue(model.Content, raw, "resourceType", "id", "meta")
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Parse "+resourceType, fmt.Sprintf("Unable to parse %s %s: %s", resourceType, model.ID.ValueString(), err))
+		return
+	}
+	model.Content = content
+	model.URL = types.StringValue(contentURL(raw))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *StructureDefinitionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model StructureDefinitionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, &model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *StructureDefinitionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model StructureDefinitionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resourceType := structureDefinitionResourceType(model.Format.ValueString())
+	if err := r.client.DeleteResource(ctx, "fhir/"+resourceType, model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete "+resourceType,
+			fmt.Sprintf("Error while trying to delete the %s with ID %s: %s", resourceType, model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *StructureDefinitionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	// Import IDs are either "<id>" for StructureDefinitions or
+	// "fhir_schema/<id>" for FHIR Schemas.
+	format, id, ok := strings.Cut(req.ID, "/")
+	if !ok {
+		format, id = structureDefinitionFormatStructureDefinition, req.ID
+	}
+	if format != structureDefinitionFormatStructureDefinition && format != structureDefinitionFormatFHIRSchema {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("Expected \"<id>\" or \"fhir_schema/<id>\", got %q.", req.ID))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("format"), format)...)
+}
+
+func (r *StructureDefinitionResource) save(ctx context.Context, model *StructureDefinitionResourceModel, diags *diag.Diagnostics) {
+	format := model.Format.ValueString()
+	resourceType := structureDefinitionResourceType(format)
+	id := model.ID.ValueString()
+
+	if format == structureDefinitionFormatFHIRSchema {
+		diags.Append(requireServerFeature(r.serverInfo(ctx), aidboxclient.FeatureFHIRSchema, "aidbox_structure_definition with format \"fhir_schema\"")...)
+		if diags.HasError() {
+			return
+		}
+	}
+
+	var content map[string]interface{}
+	if err := json.Unmarshal([]byte(model.Content.ValueString()), &content); err != nil {
+		diags.AddAttributeError(path.Root("content"), "Invalid Content", fmt.Sprintf("content must be a JSON object: %s", err))
+		return
+	}
+	if rt, ok := content["resourceType"]; ok && rt != resourceType {
+		diags.AddAttributeError(path.Root("content"), "Invalid Content", fmt.Sprintf("content has resourceType %v, expected %s for format %q.", rt, resourceType, format))
+		return
+	}
+	if contentID, ok := content["id"]; ok && contentID != id {
+		diags.AddAttributeError(path.Root("content"), "Invalid Content", fmt.Sprintf("content has id %v, which does not match the id attribute %q.", contentID, id))
+		return
+	}
+	content["resourceType"] = resourceType
+	content["id"] = id
+
+	var raw json.RawMessage
+	if err := r.client.PutResource(ctx, "fhir/"+resourceType, id, content, &raw); err != nil {
+		diags.Append(operationOutcomeDiagnostics(err, path.Root("content"), "Invalid "+resourceType)...)
+		return
+	}
+
+	url, _ := content["url"].(string)
+	if len(raw) > 0 {
+		url = contentURL(raw)
+	}
+	model.URL = types.StringValue(url)
+}
+
+func structureDefinitionResourceType(format string) string {
+	if format == structureDefinitionFormatFHIRSchema {
+		return "FHIRSchema"
+	}
+	return "StructureDefinition"
+}
+
+// contentURL returns the canonical URL of a resource.
+func contentURL(raw []byte) string {
+	var resource struct {
+		URL string `json:"url"`
+	}
+	_ = json.Unmarshal(raw, &resource)
+	return resource.URL
+}
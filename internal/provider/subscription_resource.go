@@ -0,0 +1,283 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SubscriptionResource{}
+var _ resource.ResourceWithImportState = &SubscriptionResource{}
+var _ resource.ResourceWithModifyPlan = &SubscriptionResource{}
+
+func NewSubscriptionResource() resource.Resource {
+	return &SubscriptionResource{}
+}
+
+// SubscriptionResource defines the resource implementation.
+type SubscriptionResource struct {
+	client     Client
+	serverInfo func(context.Context) *aidboxclient.ServerInfo
+}
+
+// SubscriptionResourceModel describes the resource data model.
+type SubscriptionResourceModel struct {
+	ID             types.String                            `tfsdk:"id"`
+	Status         types.String                            `tfsdk:"status"`
+	Triggers       map[string]SubsSubscriptionTriggerModel `tfsdk:"triggers"`
+	Endpoint       types.String                            `tfsdk:"endpoint"`
+	PayloadContent types.String                            `tfsdk:"payload_content"`
+	Headers        map[string]string                       `tfsdk:"headers"`
+	Timeout        types.Int64                             `tfsdk:"timeout"`
+}
+
+// SubsSubscriptionTriggerModel describes the events of a resource type.
+type SubsSubscriptionTriggerModel struct {
+	Events []types.String `tfsdk:"events"`
+	Filter types.String   `tfsdk:"filter"`
+}
+
+func (r *SubscriptionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subscription"
+}
+
+func (r *SubscriptionResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a legacy `SubsSubscription`, which POSTs resource changes to a REST hook. " +
+			"Prefer `aidbox_subscription_topic` and `aidbox_topic_destination` on recent Aidbox versions",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "SubsSubscription resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "`active` or `off`. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("active", "off"),
+				},
+			},
+			"triggers": schema.MapNestedAttribute{
+				MarkdownDescription: "Events to deliver, keyed by resource type",
+				Required:            true,
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(resourceTypeRegexp, "must be a resource type, e.g. Patient")),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"events": schema.ListAttribute{
+							MarkdownDescription: "`create`, `update`, `delete` and/or `all`. Defaults to all events",
+							ElementType:         types.StringType,
+							Optional:            true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(stringvalidator.OneOf("create", "update", "delete", "all")),
+							},
+						},
+						"filter": schema.StringAttribute{
+							MarkdownDescription: "JSON filter the resource must match, e.g. `jsonencode({ match = { gender = \"female\" } })`",
+							Optional:            true,
+							Validators: []validator.String{
+								jsonStringValidator{},
+							},
+						},
+					},
+				},
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "URL of the REST hook",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+				},
+			},
+			"payload_content": schema.StringAttribute{
+				MarkdownDescription: "`id-only` or `full-resource`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("id-only", "full-resource"),
+				},
+			},
+			"headers": schema.MapAttribute{
+				MarkdownDescription: "HTTP headers sent with each request, e.g. `Authorization`",
+				ElementType:         types.StringType,
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Request timeout in milliseconds",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+func (r *SubscriptionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+	r.serverInfo = data.ServerInfo
+}
+
+func (r *SubscriptionResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(requireServerFeatureOnCreate(ctx, req, r.serverInfo, aidboxclient.FeatureSubsSubscription, "aidbox_subscription")...)
+}
+
+func (r *SubscriptionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model SubscriptionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model SubscriptionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var subscription aidboxclient.SubsSubscription
+	err := r.client.GetResource(ctx, "SubsSubscription", model.ID.ValueString(), &subscription)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "SubsSubscription not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch SubsSubscription", fmt.Sprintf("Unable to fetch SubsSubscription %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapSubsSubscriptionToModel(&model, subscription)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model SubscriptionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model SubscriptionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "SubsSubscription", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete SubsSubscription",
+			fmt.Sprintf("Error while trying to delete the SubsSubscription with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *SubscriptionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *SubscriptionResource) save(ctx context.Context, model SubscriptionResourceModel, diags *diag.Diagnostics) {
+	subscription := aidboxclient.SubsSubscription{
+		ResourceType: "SubsSubscription",
+		ID:           model.ID.ValueString(),
+		Status:       model.Status.ValueString(),
+		Trigger:      make(map[string]aidboxclient.SubsSubscriptionTrigger, len(model.Triggers)),
+		Channel: aidboxclient.SubsSubscriptionChannel{
+			Type:     "rest-hook",
+			Endpoint: model.Endpoint.ValueString(),
+			Headers:  model.Headers,
+			Timeout:  model.Timeout.ValueInt64Pointer(),
+		},
+	}
+	if !model.PayloadContent.IsNull() {
+		subscription.Channel.Payload = &aidboxclient.SubsSubscriptionPayload{Content: model.PayloadContent.ValueString(), ContentType: "json"}
+	}
+
+	for resourceType, t := range model.Triggers {
+		trigger := aidboxclient.SubsSubscriptionTrigger{}
+		for _, e := range t.Events {
+			trigger.Event = append(trigger.Event, e.ValueString())
+		}
+		if !t.Filter.IsNull() {
+			trigger.Filter = json.RawMessage(t.Filter.ValueString())
+		}
+		subscription.Trigger[resourceType] = trigger
+	}
+
+	if err := r.client.PutResource(ctx, "SubsSubscription", subscription.ID, subscription, nil); err != nil {
+		diags.AddError("Failed to Save SubsSubscription", fmt.Sprintf("Unable to save SubsSubscription %s: %s", subscription.ID, err))
+	}
+}
+
+func mapSubsSubscriptionToModel(model *SubscriptionResourceModel, subscription aidboxclient.SubsSubscription) {
+	model.Status = types.StringValue(subscription.Status)
+	model.Endpoint = types.StringValue(subscription.Channel.Endpoint)
+	model.Headers = subscription.Channel.Headers
+	model.Timeout = types.Int64PointerValue(subscription.Channel.Timeout)
+	model.PayloadContent = types.StringNull()
+	if subscription.Channel.Payload != nil {
+		model.PayloadContent = optionalStringValue(subscription.Channel.Payload.Content)
+	}
+
+	triggers := make(map[string]SubsSubscriptionTriggerModel, len(subscription.Trigger))
+	for resourceType, t := range subscription.Trigger {
+		trigger := SubsSubscriptionTriggerModel{
+			Filter: jsonStringValue(model.Triggers[resourceType].Filter, t.Filter),
+		}
+		for _, e := range t.Event {
+			trigger.Events = append(trigger.Events, types.StringValue(e))
+		}
+		triggers[resourceType] = trigger
+	}
+	model.Triggers = triggers
+}
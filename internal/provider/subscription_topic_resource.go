@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &SubscriptionTopicResource{}
+var _ resource.ResourceWithImportState = &SubscriptionTopicResource{}
+var _ resource.ResourceWithModifyPlan = &SubscriptionTopicResource{}
+
+func NewSubscriptionTopicResource() resource.Resource {
+	return &SubscriptionTopicResource{}
+}
+
+// SubscriptionTopicResource defines the resource implementation.
+type SubscriptionTopicResource struct {
+	client     Client
+	serverInfo func(context.Context) *aidboxclient.ServerInfo
+}
+
+// SubscriptionTopicResourceModel describes the resource data model.
+type SubscriptionTopicResourceModel struct {
+	ID          types.String               `tfsdk:"id"`
+	URL         types.String               `tfsdk:"url"`
+	Status      types.String               `tfsdk:"status"`
+	Description types.String               `tfsdk:"description"`
+	Triggers    []SubscriptionTriggerModel `tfsdk:"triggers"`
+}
+
+// SubscriptionTriggerModel describes a trigger of a subscription topic.
+type SubscriptionTriggerModel struct {
+	ResourceType          types.String   `tfsdk:"resource_type"`
+	Description           types.String   `tfsdk:"description"`
+	SupportedInteractions []types.String `tfsdk:"supported_interactions"`
+	FHIRPathCriteria      types.String   `tfsdk:"fhir_path_criteria"`
+}
+
+func (r *SubscriptionTopicResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_subscription_topic"
+}
+
+func (r *SubscriptionTopicResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an `AidboxSubscriptionTopic`, which publishes resource changes matching its triggers " +
+			"to the destinations attached with `aidbox_topic_destination`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "AidboxSubscriptionTopic resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL of the topic, referenced by destinations",
+				Required:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Publication status. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("draft", "active", "retired", "unknown"),
+				},
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"triggers": schema.ListNestedAttribute{
+				MarkdownDescription: "Resource changes published to the topic",
+				Required:            true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"resource_type": schema.StringAttribute{
+							MarkdownDescription: "Resource type, e.g. `Patient`",
+							Required:            true,
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(resourceTypeRegexp, "must be a resource type, e.g. Patient"),
+							},
+						},
+						"description": schema.StringAttribute{
+							Optional: true,
+						},
+						"supported_interactions": schema.ListAttribute{
+							MarkdownDescription: "Interactions that trigger an event: `create`, `update` and/or `delete`. Defaults to all of them",
+							ElementType:         types.StringType,
+							Optional:            true,
+							Validators: []validator.List{
+								listvalidator.ValueStringsAre(stringvalidator.OneOf("create", "update", "delete")),
+							},
+						},
+						"fhir_path_criteria": schema.StringAttribute{
+							MarkdownDescription: "FHIRPath expression the resource must match, e.g. `gender = 'female'`",
+							Optional:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *SubscriptionTopicResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+	r.serverInfo = data.ServerInfo
+}
+
+func (r *SubscriptionTopicResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(requireServerFeatureOnCreate(ctx, req, r.serverInfo, aidboxclient.FeatureTopicSubscriptions, "aidbox_subscription_topic")...)
+}
+
+func (r *SubscriptionTopicResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model SubscriptionTopicResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionTopicResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model SubscriptionTopicResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var topic aidboxclient.SubscriptionTopic
+	err := r.client.GetResource(ctx, "fhir/AidboxSubscriptionTopic", model.ID.ValueString(), &topic)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "AidboxSubscriptionTopic not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch AidboxSubscriptionTopic", fmt.Sprintf("Unable to fetch AidboxSubscriptionTopic %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	model.URL = types.StringValue(topic.URL)
+	model.Status = types.StringValue(topic.Status)
+	model.Description = optionalStringValue(topic.Description)
+	model.Triggers = make([]SubscriptionTriggerModel, 0, len(topic.Trigger))
+	for _, t := range topic.Trigger {
+		trigger := SubscriptionTriggerModel{
+			ResourceType:     types.StringValue(t.Resource),
+			Description:      optionalStringValue(t.Description),
+			FHIRPathCriteria: optionalStringValue(t.FHIRPathCriteria),
+		}
+		for _, i := range t.SupportedInteraction {
+			trigger.SupportedInteractions = append(trigger.SupportedInteractions, types.StringValue(i))
+		}
+		model.Triggers = append(model.Triggers, trigger)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionTopicResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model SubscriptionTopicResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *SubscriptionTopicResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model SubscriptionTopicResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "fhir/AidboxSubscriptionTopic", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete AidboxSubscriptionTopic",
+			fmt.Sprintf("Error while trying to delete the AidboxSubscriptionTopic with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *SubscriptionTopicResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *SubscriptionTopicResource) save(ctx context.Context, model SubscriptionTopicResourceModel, diags *diag.Diagnostics) {
+	topic := aidboxclient.SubscriptionTopic{
+		ResourceType: "AidboxSubscriptionTopic",
+		ID:           model.ID.ValueString(),
+		URL:          model.URL.ValueString(),
+		Status:       model.Status.ValueString(),
+		Description:  model.Description.ValueString(),
+	}
+	for _, t := range model.Triggers {
+		trigger := aidboxclient.SubscriptionTopicTrigger{
+			Resource:         t.ResourceType.ValueString(),
+			Description:      t.Description.ValueString(),
+			FHIRPathCriteria: t.FHIRPathCriteria.ValueString(),
+		}
+		for _, i := range t.SupportedInteractions {
+			trigger.SupportedInteraction = append(trigger.SupportedInteraction, i.ValueString())
+		}
+		topic.Trigger = append(topic.Trigger, trigger)
+	}
+
+	if err := r.client.PutResource(ctx, "fhir/AidboxSubscriptionTopic", topic.ID, topic, nil); err != nil {
+		diags.AddError("Failed to Save AidboxSubscriptionTopic", fmt.Sprintf("Unable to save AidboxSubscriptionTopic %s: %s", topic.ID, err))
+	}
+}
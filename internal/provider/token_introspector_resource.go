@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TokenIntrospectorResource{}
+var _ resource.ResourceWithValidateConfig = &TokenIntrospectorResource{}
+var _ resource.ResourceWithImportState = &TokenIntrospectorResource{}
+
+func NewTokenIntrospectorResource() resource.Resource {
+	return &TokenIntrospectorResource{}
+}
+
+// TokenIntrospectorResource defines the resource implementation.
+type TokenIntrospectorResource struct {
+	client Client
+}
+
+// TokenIntrospectorResourceModel describes the resource data model.
+type TokenIntrospectorResourceModel struct {
+	ID                    types.String                     `tfsdk:"id"`
+	Type                  types.String                     `tfsdk:"type"`
+	Issuer                types.String                     `tfsdk:"issuer"`
+	JWKSURI               types.String                     `tfsdk:"jwks_uri"`
+	JWTSecret             types.String                     `tfsdk:"jwt_secret"`
+	IntrospectionEndpoint *TokenIntrospectionEndpointModel `tfsdk:"introspection_endpoint"`
+}
+
+// TokenIntrospectionEndpointModel describes an introspection endpoint.
+type TokenIntrospectionEndpointModel struct {
+	URL           types.String `tfsdk:"url"`
+	Authorization types.String `tfsdk:"authorization"`
+}
+
+func (r *TokenIntrospectorResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_token_introspector"
+}
+
+func (r *TokenIntrospectorResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an Aidbox `TokenIntrospector`, which accepts access tokens issued by an external authorization server. " +
+			"JWTs are validated against `jwks_uri` or `jwt_secret`, opaque tokens through `introspection_endpoint`",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "TokenIntrospector resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "`jwt` or `opaque`",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("jwt", "opaque"),
+				},
+			},
+			"issuer": schema.StringAttribute{
+				MarkdownDescription: "Expected `iss` claim of JWTs. Required for `jwt`",
+				Optional:            true,
+			},
+			"jwks_uri": schema.StringAttribute{
+				MarkdownDescription: "URL of the JSON Web Key Set used to verify JWT signatures",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+				},
+			},
+			"jwt_secret": schema.StringAttribute{
+				MarkdownDescription: "Shared secret used to verify HMAC-signed JWTs. Conflicts with `jwks_uri`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"introspection_endpoint": schema.SingleNestedAttribute{
+				MarkdownDescription: "RFC 7662 introspection endpoint. Required for `opaque`",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"url": schema.StringAttribute{
+						Required: true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+						},
+					},
+					"authorization": schema.StringAttribute{
+						MarkdownDescription: "`Authorization` header sent to the endpoint",
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that the attributes match the token type.
+func (r *TokenIntrospectorResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var tokenType, issuer, jwksURI, jwtSecret types.String
+	var endpoint types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &tokenType)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("issuer"), &issuer)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("jwks_uri"), &jwksURI)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("jwt_secret"), &jwtSecret)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("introspection_endpoint"), &endpoint)...)
+	if resp.Diagnostics.HasError() || tokenType.IsUnknown() {
+		return
+	}
+
+	switch tokenType.ValueString() {
+	case "jwt":
+		if issuer.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("issuer"), "Missing Issuer", "issuer is required for JWT introspectors.")
+		}
+		if jwksURI.IsNull() && jwtSecret.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("jwks_uri"), "Missing Signing Key", "One of jwks_uri or jwt_secret is required for JWT introspectors.")
+		}
+		if !jwksURI.IsNull() && !jwtSecret.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("jwt_secret"), "Conflicting Signing Keys", "Only one of jwks_uri or jwt_secret can be set.")
+		}
+		if !endpoint.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("introspection_endpoint"), "Invalid Attribute", "introspection_endpoint is only used for opaque tokens.")
+		}
+	case "opaque":
+		if endpoint.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("introspection_endpoint"), "Missing Introspection Endpoint", "introspection_endpoint is required for opaque tokens.")
+		}
+		if !jwksURI.IsNull() || !jwtSecret.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("type"), "Invalid Attribute", "jwks_uri and jwt_secret are only used for JWTs.")
+		}
+	}
+}
+
+func (r *TokenIntrospectorResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *TokenIntrospectorResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model TokenIntrospectorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TokenIntrospectorResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model TokenIntrospectorResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var introspector aidboxclient.TokenIntrospector
+	err := r.client.GetResource(ctx, "TokenIntrospector", model.ID.ValueString(), &introspector)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "TokenIntrospector not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch TokenIntrospector", fmt.Sprintf("Unable to fetch TokenIntrospector %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapTokenIntrospectorToModel(&model, introspector)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TokenIntrospectorResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model TokenIntrospectorResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TokenIntrospectorResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model TokenIntrospectorResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "TokenIntrospector", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete TokenIntrospector",
+			fmt.Sprintf("Error while trying to delete the TokenIntrospector with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *TokenIntrospectorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *TokenIntrospectorResource) save(ctx context.Context, model TokenIntrospectorResourceModel, diags *diag.Diagnostics) {
+	introspector := aidboxclient.TokenIntrospector{
+		ResourceType: "TokenIntrospector",
+		ID:           model.ID.ValueString(),
+		Type:         model.Type.ValueString(),
+		JWKSURI:      model.JWKSURI.ValueString(),
+	}
+	if !model.Issuer.IsNull() || !model.JWTSecret.IsNull() {
+		introspector.JWT = &aidboxclient.TokenIntrospectorJWT{
+			Issuer: model.Issuer.ValueString(),
+			Secret: model.JWTSecret.ValueString(),
+		}
+	}
+	if model.IntrospectionEndpoint != nil {
+		introspector.IntrospectionEndpoint = &aidboxclient.TokenIntrospectionEndpoint{
+			URL:           model.IntrospectionEndpoint.URL.ValueString(),
+			Authorization: model.IntrospectionEndpoint.Authorization.ValueString(),
+		}
+	}
+
+	if err := r.client.PutResource(ctx, "TokenIntrospector", introspector.ID, introspector, nil); err != nil {
+		diags.AddError("Failed to Save TokenIntrospector", fmt.Sprintf("Unable to save TokenIntrospector %s: %s", introspector.ID, err))
+	}
+}
+
+func mapTokenIntrospectorToModel(model *TokenIntrospectorResourceModel, introspector aidboxclient.TokenIntrospector) {
+	model.Type = types.StringValue(introspector.Type)
+	model.JWKSURI = optionalStringValue(introspector.JWKSURI)
+
+	model.Issuer = types.StringNull()
+	if introspector.JWT != nil {
+		model.Issuer = optionalStringValue(introspector.JWT.Issuer)
+		model.JWTSecret = preserveSecret(model.JWTSecret, introspector.JWT.Secret)
+	} else {
+		model.JWTSecret = types.StringNull()
+	}
+
+	if introspector.IntrospectionEndpoint == nil {
+		model.IntrospectionEndpoint = nil
+		return
+	}
+	authorization := types.StringNull()
+	if model.IntrospectionEndpoint != nil {
+		authorization = model.IntrospectionEndpoint.Authorization
+	}
+	model.IntrospectionEndpoint = &TokenIntrospectionEndpointModel{
+		URL:           types.StringValue(introspector.IntrospectionEndpoint.URL),
+		Authorization: preserveSecret(authorization, introspector.IntrospectionEndpoint.Authorization),
+	}
+}
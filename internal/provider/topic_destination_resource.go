@@ -0,0 +1,424 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &TopicDestinationResource{}
+var _ resource.ResourceWithConfigValidators = &TopicDestinationResource{}
+var _ resource.ResourceWithImportState = &TopicDestinationResource{}
+var _ resource.ResourceWithModifyPlan = &TopicDestinationResource{}
+
+const (
+	topicDestinationKindWebhook   = "webhook-at-least-once"
+	topicDestinationKindKafka     = "kafka-at-least-once"
+	topicDestinationKindGCPPubSub = "gcp-pubsub-at-least-once"
+)
+
+var httpURLRegexp = regexp.MustCompile(`^https?://[^\s/]+\S*$`)
+
+func NewTopicDestinationResource() resource.Resource {
+	return &TopicDestinationResource{}
+}
+
+// TopicDestinationResource defines the resource implementation.
+type TopicDestinationResource struct {
+	client     Client
+	serverInfo func(context.Context) *aidboxclient.ServerInfo
+}
+
+// TopicDestinationResourceModel describes the resource data model.
+type TopicDestinationResourceModel struct {
+	ID        types.String               `tfsdk:"id"`
+	Topic     types.String               `tfsdk:"topic"`
+	Kind      types.String               `tfsdk:"kind"`
+	Webhook   *WebhookDestinationModel   `tfsdk:"webhook"`
+	Kafka     *KafkaDestinationModel     `tfsdk:"kafka"`
+	GCPPubSub *GCPPubSubDestinationModel `tfsdk:"gcp_pubsub"`
+}
+
+// WebhookDestinationModel describes the parameters of a webhook destination.
+type WebhookDestinationModel struct {
+	Endpoint           types.String      `tfsdk:"endpoint"`
+	Timeout            types.Int64       `tfsdk:"timeout"`
+	KeepAlive          types.Int64       `tfsdk:"keep_alive"`
+	MaxMessagesInBatch types.Int64       `tfsdk:"max_messages_in_batch"`
+	SendIntervalMs     types.Int64       `tfsdk:"send_interval_ms"`
+	Headers            map[string]string `tfsdk:"headers"`
+}
+
+// KafkaDestinationModel describes the parameters of a Kafka destination.
+type KafkaDestinationModel struct {
+	KafkaTopic       types.String      `tfsdk:"kafka_topic"`
+	BootstrapServers types.String      `tfsdk:"bootstrap_servers"`
+	Properties       map[string]string `tfsdk:"properties"`
+}
+
+// GCPPubSubDestinationModel describes the parameters of a GCP Pub/Sub
+// destination.
+type GCPPubSubDestinationModel struct {
+	ProjectID types.String `tfsdk:"project_id"`
+	TopicID   types.String `tfsdk:"topic_id"`
+}
+
+func (r *TopicDestinationResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_topic_destination"
+}
+
+func (r *TopicDestinationResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	nonNegative := []validator.Int64{int64validator.AtLeast(0)}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages an `AidboxTopicDestination`, which delivers the events of a subscription topic to a webhook, " +
+			"Kafka or GCP Pub/Sub. Exactly one of `webhook`, `kafka` and `gcp_pubsub` must be set. Any change replaces the destination",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "AidboxTopicDestination resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"topic": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL of the subscription topic",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"kind": schema.StringAttribute{
+				MarkdownDescription: "Destination kind, derived from the configured block, e.g. `webhook-at-least-once`",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"webhook": schema.SingleNestedAttribute{
+				MarkdownDescription: "Deliver events to an HTTP endpoint",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"endpoint": schema.StringAttribute{
+						MarkdownDescription: "URL events are POSTed to",
+						Required:            true,
+						Validators: []validator.String{
+							stringvalidator.RegexMatches(httpURLRegexp, "must be an http or https URL"),
+						},
+					},
+					"timeout": schema.Int64Attribute{
+						MarkdownDescription: "Request timeout in milliseconds",
+						Optional:            true,
+						Validators:          nonNegative,
+					},
+					"keep_alive": schema.Int64Attribute{
+						MarkdownDescription: "HTTP keep-alive in milliseconds, `-1` to disable",
+						Optional:            true,
+					},
+					"max_messages_in_batch": schema.Int64Attribute{
+						MarkdownDescription: "Maximum number of events per request",
+						Optional:            true,
+						Validators:          nonNegative,
+					},
+					"send_interval_ms": schema.Int64Attribute{
+						MarkdownDescription: "Maximum time to wait for a batch to fill, in milliseconds",
+						Optional:            true,
+						Validators:          nonNegative,
+					},
+					"headers": schema.MapAttribute{
+						MarkdownDescription: "HTTP headers sent with each request, e.g. `Authorization`",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"kafka": schema.SingleNestedAttribute{
+				MarkdownDescription: "Deliver events to a Kafka topic",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"kafka_topic": schema.StringAttribute{
+						MarkdownDescription: "Kafka topic name",
+						Required:            true,
+					},
+					"bootstrap_servers": schema.StringAttribute{
+						MarkdownDescription: "Comma-separated list of Kafka brokers, e.g. `kafka:9092`",
+						Required:            true,
+					},
+					"properties": schema.MapAttribute{
+						MarkdownDescription: "Additional destination parameters by Aidbox name, e.g. `securityProtocol` or `saslJaasConfig`",
+						ElementType:         types.StringType,
+						Optional:            true,
+						Sensitive:           true,
+					},
+				},
+			},
+			"gcp_pubsub": schema.SingleNestedAttribute{
+				MarkdownDescription: "Deliver events to a GCP Pub/Sub topic",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"project_id": schema.StringAttribute{
+						MarkdownDescription: "GCP project ID",
+						Required:            true,
+					},
+					"topic_id": schema.StringAttribute{
+						MarkdownDescription: "Pub/Sub topic ID",
+						Required:            true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *TopicDestinationResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("webhook"),
+			path.MatchRoot("kafka"),
+			path.MatchRoot("gcp_pubsub"),
+		),
+	}
+}
+
+func (r *TopicDestinationResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+	r.serverInfo = data.ServerInfo
+}
+
+func (r *TopicDestinationResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(requireServerFeatureOnCreate(ctx, req, r.serverInfo, aidboxclient.FeatureTopicSubscriptions, "aidbox_topic_destination")...)
+}
+
+func (r *TopicDestinationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model TopicDestinationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	destination := topicDestinationFromModel(model)
+	if err := r.client.PutResource(ctx, "fhir/AidboxTopicDestination", destination.ID, destination, nil); err != nil {
+		resp.Diagnostics.AddError("Failed to Create AidboxTopicDestination", fmt.Sprintf("Unable to create AidboxTopicDestination %s: %s", destination.ID, err))
+		return
+	}
+
+	model.Kind = types.StringValue(destination.Kind)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TopicDestinationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model TopicDestinationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var destination aidboxclient.TopicDestination
+	err := r.client.GetResource(ctx, "fhir/AidboxTopicDestination", model.ID.ValueString(), &destination)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "AidboxTopicDestination not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch AidboxTopicDestination", fmt.Sprintf("Unable to fetch AidboxTopicDestination %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	if err := mapTopicDestinationToModel(&model, destination); err != nil {
+		resp.Diagnostics.AddError("Unsupported AidboxTopicDestination", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TopicDestinationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model TopicDestinationResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every configurable attribute forces a replacement.
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *TopicDestinationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model TopicDestinationResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "fhir/AidboxTopicDestination", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete AidboxTopicDestination",
+			fmt.Sprintf("Error while trying to delete the AidboxTopicDestination with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *TopicDestinationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func topicDestinationFromModel(model TopicDestinationResourceModel) aidboxclient.TopicDestination {
+	destination := aidboxclient.TopicDestination{
+		ResourceType: "AidboxTopicDestination",
+		ID:           model.ID.ValueString(),
+		Topic:        model.Topic.ValueString(),
+	}
+
+	stringParam := func(name, value string) {
+		destination.Parameter = append(destination.Parameter, aidboxclient.TopicDestinationParameter{Name: name, ValueString: value})
+	}
+	unsignedParam := func(name string, value types.Int64) {
+		if !value.IsNull() {
+			destination.Parameter = append(destination.Parameter, aidboxclient.TopicDestinationParameter{Name: name, ValueUnsignedInt: value.ValueInt64Pointer()})
+		}
+	}
+
+	switch {
+	case model.Webhook != nil:
+		destination.Kind = topicDestinationKindWebhook
+		destination.Parameter = append(destination.Parameter, aidboxclient.TopicDestinationParameter{Name: "endpoint", ValueURL: model.Webhook.Endpoint.ValueString()})
+		unsignedParam("timeout", model.Webhook.Timeout)
+		if !model.Webhook.KeepAlive.IsNull() {
+			destination.Parameter = append(destination.Parameter, aidboxclient.TopicDestinationParameter{Name: "keepAlive", ValueInteger: model.Webhook.KeepAlive.ValueInt64Pointer()})
+		}
+		unsignedParam("maxMessagesInBatch", model.Webhook.MaxMessagesInBatch)
+		unsignedParam("sendIntervalMs", model.Webhook.SendIntervalMs)
+		for _, name := range sortedKeys(model.Webhook.Headers) {
+			stringParam("header", name+": "+model.Webhook.Headers[name])
+		}
+	case model.Kafka != nil:
+		destination.Kind = topicDestinationKindKafka
+		stringParam("kafkaTopic", model.Kafka.KafkaTopic.ValueString())
+		stringParam("bootstrapServers", model.Kafka.BootstrapServers.ValueString())
+		for _, name := range sortedKeys(model.Kafka.Properties) {
+			stringParam(name, model.Kafka.Properties[name])
+		}
+	case model.GCPPubSub != nil:
+		destination.Kind = topicDestinationKindGCPPubSub
+		stringParam("projectId", model.GCPPubSub.ProjectID.ValueString())
+		stringParam("topicId", model.GCPPubSub.TopicID.ValueString())
+	}
+
+	destination.Meta = &aidboxclient.ResourceMeta{Profile: []string{aidboxclient.TopicDestinationProfile(destination.Kind)}}
+	return destination
+}
+
+func mapTopicDestinationToModel(model *TopicDestinationResourceModel, destination aidboxclient.TopicDestination) error {
+	model.Topic = types.StringValue(destination.Topic)
+	model.Kind = types.StringValue(destination.Kind)
+	model.Webhook, model.Kafka, model.GCPPubSub = nil, nil, nil
+
+	switch destination.Kind {
+	case topicDestinationKindWebhook:
+		webhook := &WebhookDestinationModel{
+			Timeout:            types.Int64Null(),
+			KeepAlive:          types.Int64Null(),
+			MaxMessagesInBatch: types.Int64Null(),
+			SendIntervalMs:     types.Int64Null(),
+		}
+		for _, p := range destination.Parameter {
+			switch p.Name {
+			case "endpoint":
+				webhook.Endpoint = types.StringValue(p.ValueURL)
+			case "timeout":
+				webhook.Timeout = types.Int64PointerValue(p.ValueUnsignedInt)
+			case "keepAlive":
+				webhook.KeepAlive = types.Int64PointerValue(p.ValueInteger)
+			case "maxMessagesInBatch":
+				webhook.MaxMessagesInBatch = types.Int64PointerValue(p.ValueUnsignedInt)
+			case "sendIntervalMs":
+				webhook.SendIntervalMs = types.Int64PointerValue(p.ValueUnsignedInt)
+			case "header":
+				if webhook.Headers == nil {
+					webhook.Headers = map[string]string{}
+				}
+				name, value, _ := strings.Cut(p.ValueString, ":")
+				webhook.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+		}
+		model.Webhook = webhook
+	case topicDestinationKindKafka:
+		kafka := &KafkaDestinationModel{}
+		for _, p := range destination.Parameter {
+			switch p.Name {
+			case "kafkaTopic":
+				kafka.KafkaTopic = types.StringValue(p.ValueString)
+			case "bootstrapServers":
+				kafka.BootstrapServers = types.StringValue(p.ValueString)
+			default:
+				if kafka.Properties == nil {
+					kafka.Properties = map[string]string{}
+				}
+				kafka.Properties[p.Name] = p.ValueString
+			}
+		}
+		model.Kafka = kafka
+	case topicDestinationKindGCPPubSub:
+		pubsub := &GCPPubSubDestinationModel{}
+		for _, p := range destination.Parameter {
+			switch p.Name {
+			case "projectId":
+				pubsub.ProjectID = types.StringValue(p.ValueString)
+			case "topicId":
+				pubsub.TopicID = types.StringValue(p.ValueString)
+			}
+		}
+		model.GCPPubSub = pubsub
+	default:
+		return fmt.Errorf("AidboxTopicDestination %s has kind %q, which is not supported by this provider", destination.ID, destination.Kind)
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of m in a stable order, so that generated
+// parameter lists do not change between runs.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestTopicDestinationRoundTrip(t *testing.T) {
+	cases := map[string]TopicDestinationResourceModel{
+		topicDestinationKindWebhook: {
+			Webhook: &WebhookDestinationModel{
+				Endpoint:           types.StringValue("https://hooks.example.com/aidbox"),
+				Timeout:            types.Int64Value(5000),
+				KeepAlive:          types.Int64Value(-1),
+				MaxMessagesInBatch: types.Int64Null(),
+				SendIntervalMs:     types.Int64Value(1000),
+				Headers:            map[string]string{"Authorization": "Bearer secret"},
+			},
+		},
+		topicDestinationKindKafka: {
+			Kafka: &KafkaDestinationModel{
+				KafkaTopic:       types.StringValue("aidbox-patients"),
+				BootstrapServers: types.StringValue("kafka:9092"),
+				Properties:       map[string]string{"securityProtocol": "SASL_SSL"},
+			},
+		},
+		topicDestinationKindGCPPubSub: {
+			GCPPubSub: &GCPPubSubDestinationModel{
+				ProjectID: types.StringValue("my-project"),
+				TopicID:   types.StringValue("aidbox-patients"),
+			},
+		},
+	}
+
+	for kind, model := range cases {
+		model.ID = types.StringValue("patients")
+		model.Topic = types.StringValue("http://example.org/topic/patients")
+
+		destination := topicDestinationFromModel(model)
+		if destination.Kind != kind {
+			t.Errorf("expected kind %q, got %q", kind, destination.Kind)
+		}
+
+		var got TopicDestinationResourceModel
+		got.ID = model.ID
+		if err := mapTopicDestinationToModel(&got, destination); err != nil {
+			t.Fatalf("%s: %s", kind, err)
+		}
+		model.Kind = types.StringValue(kind)
+		if !reflect.DeepEqual(got, model) {
+			t.Errorf("%s: expected %+v, got %+v", kind, model, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ValueSetExpansionDataSource{}
+
+func NewValueSetExpansionDataSource() datasource.DataSource {
+	return &ValueSetExpansionDataSource{}
+}
+
+// ValueSetExpansionDataSource defines the data source implementation.
+type ValueSetExpansionDataSource struct {
+	client Client
+}
+
+// ValueSetExpansionDataSourceModel describes the data source data model.
+type ValueSetExpansionDataSourceModel struct {
+	URL      types.String                  `tfsdk:"url"`
+	Filter   types.String                  `tfsdk:"filter"`
+	Limit    types.Int64                   `tfsdk:"limit"`
+	Total    types.Int64                   `tfsdk:"total"`
+	Contains []ValueSetExpansionEntryModel `tfsdk:"contains"`
+}
+
+// ValueSetExpansionEntryModel describes a code of the expansion.
+type ValueSetExpansionEntryModel struct {
+	System  types.String `tfsdk:"system"`
+	Version types.String `tfsdk:"version"`
+	Code    types.String `tfsdk:"code"`
+	Display types.String `tfsdk:"display"`
+}
+
+func (d *ValueSetExpansionDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_value_set_expansion"
+}
+
+func (d *ValueSetExpansionDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Expands a value set through `ValueSet/$expand`, e.g. to check its contents with a `check` block or a `postcondition`",
+		Attributes: map[string]schema.Attribute{
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL of the value set",
+				Required:            true,
+			},
+			"filter": schema.StringAttribute{
+				MarkdownDescription: "Text filter applied to the codes",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of codes to return, sent as the `count` parameter",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "Total number of codes in the value set, when reported by the server",
+				Computed:            true,
+			},
+			"contains": schema.ListNestedAttribute{
+				MarkdownDescription: "Codes of the expansion",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"system": schema.StringAttribute{
+							Computed: true,
+						},
+						"version": schema.StringAttribute{
+							Computed: true,
+						},
+						"code": schema.StringAttribute{
+							Computed: true,
+						},
+						"display": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ValueSetExpansionDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	d.client = data.Client
+}
+
+func (d *ValueSetExpansionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var model ValueSetExpansionDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	expansion, err := d.client.ExpandValueSet(ctx, model.URL.ValueString(), model.Filter.ValueString(), model.Limit.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Expand ValueSet", fmt.Sprintf("Unable to expand ValueSet %s: %s", model.URL.ValueString(), err))
+		return
+	}
+
+	model.Total = types.Int64PointerValue(expansion.Total)
+	model.Contains = make([]ValueSetExpansionEntryModel, 0, len(expansion.Contains))
+	for _, e := range expansion.Contains {
+		model.Contains = append(model.Contains, ValueSetExpansionEntryModel{
+			System:  types.StringValue(e.System),
+			Version: optionalStringValue(e.Version),
+			Code:    types.StringValue(e.Code),
+			Display: optionalStringValue(e.Display),
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
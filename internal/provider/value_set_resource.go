@@ -0,0 +1,331 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-aidbox/internal/aidboxclient"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ValueSetResource{}
+var _ resource.ResourceWithImportState = &ValueSetResource{}
+
+func NewValueSetResource() resource.Resource {
+	return &ValueSetResource{}
+}
+
+// ValueSetResource defines the resource implementation.
+type ValueSetResource struct {
+	client Client
+}
+
+// ValueSetResourceModel describes the resource data model.
+type ValueSetResourceModel struct {
+	ID      types.String           `tfsdk:"id"`
+	URL     types.String           `tfsdk:"url"`
+	Name    types.String           `tfsdk:"name"`
+	Version types.String           `tfsdk:"version"`
+	Status  types.String           `tfsdk:"status"`
+	Include []ValueSetIncludeModel `tfsdk:"include"`
+	Exclude []ValueSetIncludeModel `tfsdk:"exclude"`
+}
+
+// ValueSetIncludeModel describes codes included in or excluded from the
+// value set.
+type ValueSetIncludeModel struct {
+	System    types.String           `tfsdk:"system"`
+	Version   types.String           `tfsdk:"version"`
+	Concepts  []ValueSetConceptModel `tfsdk:"concepts"`
+	Filters   []ValueSetFilterModel  `tfsdk:"filters"`
+	ValueSets []types.String         `tfsdk:"value_sets"`
+}
+
+// ValueSetConceptModel describes a listed code.
+type ValueSetConceptModel struct {
+	Code    types.String `tfsdk:"code"`
+	Display types.String `tfsdk:"display"`
+}
+
+// ValueSetFilterModel describes a property filter.
+type ValueSetFilterModel struct {
+	Property types.String `tfsdk:"property"`
+	Op       types.String `tfsdk:"op"`
+	Value    types.String `tfsdk:"value"`
+}
+
+func valueSetIncludeSchema(description string, required bool) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		MarkdownDescription: description,
+		Required:            required,
+		Optional:            !required,
+		Validators: []validator.List{
+			listvalidator.SizeAtLeast(1),
+		},
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"system": schema.StringAttribute{
+					MarkdownDescription: "Code system URL",
+					Optional:            true,
+				},
+				"version": schema.StringAttribute{
+					MarkdownDescription: "Code system version",
+					Optional:            true,
+				},
+				"concepts": schema.ListNestedAttribute{
+					MarkdownDescription: "Codes of `system`. When neither `concepts` nor `filters` are set, all codes are selected",
+					Optional:            true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"code": schema.StringAttribute{
+								Required: true,
+							},
+							"display": schema.StringAttribute{
+								Optional: true,
+							},
+						},
+					},
+				},
+				"filters": schema.ListNestedAttribute{
+					MarkdownDescription: "Property filters on the codes of `system`",
+					Optional:            true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"property": schema.StringAttribute{
+								Required: true,
+							},
+							"op": schema.StringAttribute{
+								MarkdownDescription: "Filter operator, e.g. `=`, `is-a` or `regex`",
+								Required:            true,
+								Validators: []validator.String{
+									stringvalidator.OneOf("=", "is-a", "descendent-of", "is-not-a", "regex", "in", "not-in", "generalizes", "exists"),
+								},
+							},
+							"value": schema.StringAttribute{
+								Required: true,
+							},
+						},
+					},
+				},
+				"value_sets": schema.ListAttribute{
+					MarkdownDescription: "URLs of value sets whose codes are selected",
+					ElementType:         types.StringType,
+					Optional:            true,
+				},
+			},
+		},
+	}
+}
+
+func (r *ValueSetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_value_set"
+}
+
+func (r *ValueSetResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Manages a FHIR `ValueSet` of an Aidbox instance. Use the `aidbox_value_set_expansion` data source to check its contents",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "ValueSet resource ID",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "Canonical URL",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				Optional: true,
+			},
+			"version": schema.StringAttribute{
+				Optional: true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Publication status. Defaults to `active`",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("active"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(publicationStatuses...),
+				},
+			},
+			"include": valueSetIncludeSchema("Codes included in the value set", true),
+			"exclude": valueSetIncludeSchema("Codes excluded from the value set", false),
+		},
+	}
+}
+
+func (r *ValueSetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := instanceProviderData(req.ProviderData, &resp.Diagnostics)
+	if data == nil {
+		return
+	}
+
+	r.client = data.Client
+}
+
+func (r *ValueSetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var model ValueSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ValueSetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var model ValueSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var vs aidboxclient.ValueSet
+	err := r.client.GetResource(ctx, "fhir/ValueSet", model.ID.ValueString(), &vs)
+	if aidboxclient.IsNotFound(err) {
+		tflog.Warn(ctx, "ValueSet not found, removing from state", map[string]interface{}{"id": model.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to Fetch ValueSet", fmt.Sprintf("Unable to fetch ValueSet %s: %s", model.ID.ValueString(), err))
+		return
+	}
+
+	mapValueSetToModel(&model, vs)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ValueSetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var model ValueSetResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.save(ctx, model, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &model)...)
+}
+
+func (r *ValueSetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var model ValueSetResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.client.DeleteResource(ctx, "fhir/ValueSet", model.ID.ValueString()); err != nil {
+		resp.Diagnostics.AddError(
+			"Failed to Delete ValueSet",
+			fmt.Sprintf("Error while trying to delete the ValueSet with ID %s: %s", model.ID.ValueString(), err),
+		)
+	}
+}
+
+func (r *ValueSetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *ValueSetResource) save(ctx context.Context, model ValueSetResourceModel, diags *diag.Diagnostics) {
+	vs := aidboxclient.ValueSet{
+		ResourceType: "ValueSet",
+		ID:           model.ID.ValueString(),
+		URL:          model.URL.ValueString(),
+		Name:         model.Name.ValueString(),
+		Version:      model.Version.ValueString(),
+		Status:       model.Status.ValueString(),
+		Compose: &aidboxclient.ValueSetCompose{
+			Include: valueSetIncludesFromModel(model.Include),
+			Exclude: valueSetIncludesFromModel(model.Exclude),
+		},
+	}
+
+	if err := r.client.PutResource(ctx, "fhir/ValueSet", vs.ID, vs, nil); err != nil {
+		diags.Append(operationOutcomeDiagnostics(err, path.Root("include"), "Failed to Save ValueSet")...)
+	}
+}
+
+func valueSetIncludesFromModel(includes []ValueSetIncludeModel) []aidboxclient.ValueSetInclude {
+	var result []aidboxclient.ValueSetInclude
+	for _, i := range includes {
+		include := aidboxclient.ValueSetInclude{
+			System:  i.System.ValueString(),
+			Version: i.Version.ValueString(),
+		}
+		for _, c := range i.Concepts {
+			include.Concept = append(include.Concept, aidboxclient.ValueSetConcept{Code: c.Code.ValueString(), Display: c.Display.ValueString()})
+		}
+		for _, f := range i.Filters {
+			include.Filter = append(include.Filter, aidboxclient.ValueSetFilter{Property: f.Property.ValueString(), Op: f.Op.ValueString(), Value: f.Value.ValueString()})
+		}
+		for _, v := range i.ValueSets {
+			include.ValueSet = append(include.ValueSet, v.ValueString())
+		}
+		result = append(result, include)
+	}
+	return result
+}
+
+func valueSetIncludesToModel(includes []aidboxclient.ValueSetInclude) []ValueSetIncludeModel {
+	var result []ValueSetIncludeModel
+	for _, i := range includes {
+		include := ValueSetIncludeModel{
+			System:  optionalStringValue(i.System),
+			Version: optionalStringValue(i.Version),
+		}
+		for _, c := range i.Concept {
+			include.Concepts = append(include.Concepts, ValueSetConceptModel{Code: types.StringValue(c.Code), Display: optionalStringValue(c.Display)})
+		}
+		for _, f := range i.Filter {
+			include.Filters = append(include.Filters, ValueSetFilterModel{
+				Property: types.StringValue(f.Property),
+				Op:       types.StringValue(f.Op),
+				Value:    types.StringValue(f.Value),
+			})
+		}
+		for _, v := range i.ValueSet {
+			include.ValueSets = append(include.ValueSets, types.StringValue(v))
+		}
+		result = append(result, include)
+	}
+	return result
+}
+
+func mapValueSetToModel(model *ValueSetResourceModel, vs aidboxclient.ValueSet) {
+	model.URL = types.StringValue(vs.URL)
+	model.Name = optionalStringValue(vs.Name)
+	model.Version = optionalStringValue(vs.Version)
+	model.Status = types.StringValue(vs.Status)
+	model.Include, model.Exclude = nil, nil
+	if vs.Compose != nil {
+		model.Include = valueSetIncludesToModel(vs.Compose.Include)
+		model.Exclude = valueSetIncludesToModel(vs.Compose.Exclude)
+	}
+}